@@ -0,0 +1,170 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// athenzTokenValidity is how long a self-signed Athenz principal token (ntoken) this
+// provider mints is valid for before ZTS rejects it, matching the Athenz default.
+const athenzTokenValidity = 2 * time.Hour
+
+// athenzExpiryMargin renews the cached ZTS role token this long before it actually
+// expires.
+const athenzExpiryMargin = time.Minute
+
+// AthenzParams configures NewAuthenticationAthenz. ProviderDomain, TenantDomain and
+// TenantService identify the Athenz principal; PrivateKeyPath/KeyID sign the principal
+// token ZTS exchanges for a role token.
+type AthenzParams struct {
+	ProviderDomain string
+	TenantDomain   string
+	TenantService  string
+	PrivateKeyPath string
+	KeyID          string
+	ZTSURL         string
+}
+
+// athenzProvider authenticates by minting a self-signed Athenz principal token (ntoken)
+// and exchanging it with ZTS for a role token, caching the role token until shortly
+// before it expires.
+type athenzProvider struct {
+	params     AthenzParams
+	privateKey *rsa.PrivateKey
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewAuthenticationAthenz returns a Provider that authenticates against an Athenz-
+// fronted broker, exchanging a principal token signed with the private key at
+// params.PrivateKeyPath for a ZTS role token scoped to params.ProviderDomain.
+func NewAuthenticationAthenz(params AthenzParams) (Provider, error) {
+	keyPEM, err := os.ReadFile(params.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("auth: reading Athenz private key %s: %w", params.PrivateKeyPath, err)
+	}
+
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("auth: %s does not contain a PEM-encoded private key", params.PrivateKeyPath)
+	}
+
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("auth: parsing Athenz private key %s: %w", params.PrivateKeyPath, err)
+	}
+
+	return &athenzProvider{
+		params:     params,
+		privateKey: key,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (p *athenzProvider) Name() string { return "athenz" }
+
+func (p *athenzProvider) GetData() ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Now().Before(p.expiresAt) {
+		return []byte(p.token), nil
+	}
+
+	ntoken, err := p.signPrincipalToken()
+	if err != nil {
+		return nil, err
+	}
+
+	roleToken, expiresAt, err := p.fetchRoleToken(ntoken)
+	if err != nil {
+		return nil, err
+	}
+
+	p.token = roleToken
+	p.expiresAt = expiresAt.Add(-athenzExpiryMargin)
+
+	return []byte(p.token), nil
+}
+
+// signPrincipalToken builds and signs an Athenz ntoken identifying this client as
+// params.TenantDomain.params.TenantService, per the Athenz Principal Authority format.
+func (p *athenzProvider) signPrincipalToken() (string, error) {
+	now := time.Now()
+	unsigned := fmt.Sprintf("v=S1;d=%s;n=%s;k=%s;h=%s;t=%d;e=%d",
+		p.params.TenantDomain, p.params.TenantService, p.params.KeyID, p.params.TenantDomain,
+		now.Unix(), now.Add(athenzTokenValidity).Unix())
+
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, p.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("auth: signing Athenz principal token: %w", err)
+	}
+
+	return fmt.Sprintf("%s;s=%s", unsigned, base64.RawURLEncoding.EncodeToString(sig)), nil
+}
+
+// fetchRoleToken exchanges ntoken with ZTS for a role token scoped to params.ProviderDomain.
+func (p *athenzProvider) fetchRoleToken(ntoken string) (string, time.Time, error) {
+	reqURL := fmt.Sprintf("%s/zts/v1/domain/%s/token", strings.TrimRight(p.params.ZTSURL, "/"), p.params.ProviderDomain)
+
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Athenz-Principal-Auth", ntoken)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("auth: fetching ZTS role token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("auth: ZTS returned %s", resp.Status)
+	}
+
+	var body struct {
+		Token      string `json:"token"`
+		ExpiryTime int64  `json:"expiryTime"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", time.Time{}, fmt.Errorf("auth: decoding ZTS response: %w", err)
+	}
+
+	return body.Token, time.Unix(body.ExpiryTime, 0), nil
+}
@@ -0,0 +1,48 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+// Package auth provides the pluggable authentication schemes a Client can present to a
+// broker on CommandConnect.
+package auth
+
+import "crypto/tls"
+
+// Provider supplies the credentials a Client presents on every connection it opens to a
+// broker.
+type Provider interface {
+	// Name identifies the authentication method to the broker, e.g. "tls", "token" or
+	// "athenz". Sent as CommandConnect.AuthMethodName.
+	Name() string
+
+	// GetData returns the auth_data bytes to send on CommandConnect. Called again
+	// before each new connection, so providers backed by an expiring credential (OAuth2,
+	// Athenz) can refresh it.
+	GetData() ([]byte, error)
+}
+
+// CertificateProvider is implemented by Providers (currently only the one returned by
+// NewAuthenticationTLS) that authenticate via a client certificate presented during the
+// TLS handshake itself, rather than (or in addition to) CommandConnect.AuthData.
+type CertificateProvider interface {
+	Provider
+
+	// Certificate returns the client certificate/key pair to present during the TLS
+	// handshake.
+	Certificate() (tls.Certificate, error)
+}
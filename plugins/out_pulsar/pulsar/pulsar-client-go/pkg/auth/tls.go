@@ -0,0 +1,43 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package auth
+
+import "crypto/tls"
+
+// tlsProvider authenticates with a client certificate presented during the TLS
+// handshake; it carries no CommandConnect.AuthData of its own.
+type tlsProvider struct {
+	certPath string
+	keyPath  string
+}
+
+// NewAuthenticationTLS returns a Provider that authenticates with the client certificate
+// and private key PEM files at certPath and keyPath.
+func NewAuthenticationTLS(certPath, keyPath string) Provider {
+	return &tlsProvider{certPath: certPath, keyPath: keyPath}
+}
+
+func (p *tlsProvider) Name() string { return "tls" }
+
+func (p *tlsProvider) GetData() ([]byte, error) { return nil, nil }
+
+func (p *tlsProvider) Certificate() (tls.Certificate, error) {
+	return tls.LoadX509KeyPair(p.certPath, p.keyPath)
+}
@@ -0,0 +1,66 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package auth
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// tokenProvider authenticates with a JWT obtained from supplier, sent verbatim as
+// auth_data under the "token" method.
+type tokenProvider struct {
+	supplier func() (string, error)
+}
+
+// NewAuthenticationToken returns a Provider that always sends token as-is.
+func NewAuthenticationToken(token string) Provider {
+	return NewAuthenticationTokenFromSupplier(func() (string, error) { return token, nil })
+}
+
+// NewAuthenticationTokenFromFile returns a Provider that re-reads the token from path on
+// every connection, so a token rotated on disk (e.g. by a sidecar) takes effect without
+// restarting the client.
+func NewAuthenticationTokenFromFile(path string) Provider {
+	return NewAuthenticationTokenFromSupplier(func() (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("auth: reading token file %s: %w", path, err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	})
+}
+
+// NewAuthenticationTokenFromSupplier returns a Provider that calls supplier for the token
+// to send on every connection, for tokens minted or rotated by application code.
+func NewAuthenticationTokenFromSupplier(supplier func() (string, error)) Provider {
+	return &tokenProvider{supplier: supplier}
+}
+
+func (p *tokenProvider) Name() string { return "token" }
+
+func (p *tokenProvider) GetData() ([]byte, error) {
+	token, err := p.supplier()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(token), nil
+}
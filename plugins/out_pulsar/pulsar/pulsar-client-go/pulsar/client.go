@@ -0,0 +1,72 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsar
+
+import (
+	"time"
+
+	"github.com/apache/pulsar-client-go/pkg/auth"
+)
+
+// ClientOptions configures a Client created with NewClient.
+type ClientOptions struct {
+	// URL is the service URL of the Pulsar cluster, e.g. "pulsar://localhost:6650".
+	// Required.
+	URL string
+
+	// Authentication, when set, is presented on every connection the client opens, via
+	// CommandConnect's AuthMethodName/AuthData. See package
+	// github.com/apache/pulsar-client-go/pkg/auth for the available providers.
+	Authentication auth.Provider
+
+	// OperationTimeout is accepted but not read by this client: dialing a broker and the
+	// CommandConnect handshake each give up after a fixed 10 seconds, and other broker
+	// requests wait until the broker answers or the connection closes.
+	OperationTimeout time.Duration
+
+	// IOThreads is accepted but not read by this client; each connection is served by
+	// its own goroutines.
+	IOThreads int
+
+	// MessageListenerThreads is accepted but not read by this client.
+	MessageListenerThreads int
+}
+
+// Client represents a connection to a Pulsar cluster from which producers, consumers and
+// readers are created.
+type Client interface {
+	// CreateProducer creates a Producer for the configured topic.
+	CreateProducer(ProducerOptions) (Producer, error)
+
+	// CreateReader creates a Reader for the configured topic(s), starting at StartMessageID.
+	CreateReader(ReaderOptions) (Reader, error)
+
+	// Subscribe creates a Consumer for the configured topic(s) and subscription.
+	Subscribe(ConsumerOptions) (Consumer, error)
+
+	// Close releases all the resources held by the client and any producer, consumer or
+	// reader created from it.
+	Close() error
+}
+
+// NewClient creates a new Client with the given options.
+func NewClient(options ClientOptions) (Client, error) {
+	return newClient(options)
+}
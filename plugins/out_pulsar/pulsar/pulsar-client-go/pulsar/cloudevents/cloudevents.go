@@ -0,0 +1,57 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+// Package cloudevents adapts pulsar.ProducerMessage/pulsar.Message to the CNCF
+// CloudEvents v1.0 spec, mirroring the protocol binding pattern used by the other
+// github.com/cloudevents/sdk-go/v2 protocol bindings (HTTP, Kafka, NATS, ...), so Pulsar
+// can be used as a first-class CloudEvents transport alongside them. Two content modes
+// are supported:
+//
+//   - Binary: each CloudEvents context attribute becomes a "ce-<attribute>" message
+//     property (e.g. "ce-id", "ce-source"), and the event's data is sent unencoded as
+//     the message Payload.
+//   - Structured: the entire event -- context attributes and data -- is encoded as a
+//     single application/cloudevents+json document sent as the message Payload.
+package cloudevents
+
+// ContentMode selects how an Event is encoded onto, or decoded from, a Pulsar message.
+type ContentMode int
+
+const (
+	// Binary is WriteMessage's default content mode; see the package doc.
+	Binary ContentMode = iota
+
+	// Structured content mode; see the package doc.
+	Structured
+)
+
+const (
+	// contentTypeProperty carries the event's data content type in Binary mode. In
+	// Structured mode the whole Payload is this content type instead, and ToEvent uses
+	// the property's value to recognize a Structured-mode message.
+	contentTypeProperty = "content-type"
+
+	// structuredContentType is both the Payload's content type in Structured mode, and
+	// the contentTypeProperty value ToEvent looks for to recognize one.
+	structuredContentType = "application/cloudevents+json"
+
+	// propertyPrefix marks a Binary-mode property as a CloudEvents context attribute or
+	// extension, e.g. "ce-id", "ce-source", "ce-myextension".
+	propertyPrefix = "ce-"
+)
@@ -0,0 +1,92 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package cloudevents
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// ToEvent decodes msg back into an Event, auto-detecting the content mode it was written
+// with: Structured if msg's contentTypeProperty is structuredContentType, Binary
+// otherwise.
+func ToEvent(msg pulsar.Message) (event.Event, error) {
+	if msg.Properties()[contentTypeProperty] == structuredContentType {
+		return decodeStructured(msg)
+	}
+	return decodeBinary(msg)
+}
+
+func decodeStructured(msg pulsar.Message) (event.Event, error) {
+	var ev event.Event
+	if err := json.Unmarshal(msg.Payload(), &ev); err != nil {
+		return event.Event{}, fmt.Errorf("cloudevents: decoding structured event: %w", err)
+	}
+	return ev, nil
+}
+
+func decodeBinary(msg pulsar.Message) (event.Event, error) {
+	ev := event.New()
+
+	for key, value := range msg.Properties() {
+		if !strings.HasPrefix(key, propertyPrefix) {
+			continue
+		}
+
+		switch attr := strings.TrimPrefix(key, propertyPrefix); attr {
+		case "id":
+			ev.SetID(value)
+		case "source":
+			ev.SetSource(value)
+		case "specversion":
+			ev.SetSpecVersion(value)
+		case "type":
+			ev.SetType(value)
+		case "subject":
+			ev.SetSubject(value)
+		case "dataschema":
+			ev.SetDataSchema(value)
+		case "time":
+			t, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return event.Event{}, fmt.Errorf("cloudevents: invalid %s%s property %q: %w", propertyPrefix, attr, value, err)
+			}
+			ev.SetTime(t)
+		default:
+			ev.SetExtension(attr, value)
+		}
+	}
+
+	if err := ev.SetData(msg.Properties()[contentTypeProperty], msg.Payload()); err != nil {
+		return event.Event{}, fmt.Errorf("cloudevents: setting event data: %w", err)
+	}
+
+	if err := ev.Validate(); err != nil {
+		return event.Event{}, fmt.Errorf("cloudevents: invalid event: %w", err)
+	}
+
+	return ev, nil
+}
@@ -0,0 +1,173 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsar
+
+import (
+	"context"
+	"time"
+)
+
+// defaultAutoDiscoveryPeriod is how often a multi-topic/regex consumer or reader polls
+// the broker for topics added to or removed from a namespace, when
+// ConsumerOptions.AutoDiscoveryPeriod / ReaderOptions.AutoDiscoveryPeriod isn't set.
+const defaultAutoDiscoveryPeriod = 60 * time.Second
+
+// defaultNackRedeliveryDelay is how long a Nacked message waits before the broker
+// redelivers it, when ConsumerOptions.NackRedeliveryDelay isn't set.
+const defaultNackRedeliveryDelay = time.Minute
+
+// SubscriptionType selects how messages are distributed among the consumers sharing a
+// subscription.
+type SubscriptionType int
+
+const (
+	Exclusive SubscriptionType = iota
+	Shared
+	Failover
+
+	// KeyShared distributes messages among the consumers sharing the subscription by
+	// hashing each message's Key, so all messages for a given key are always delivered
+	// to the same consumer.
+	KeyShared
+)
+
+// DLQPolicy configures a Consumer's dead letter queue. Once a message has been
+// delivered MaxDeliveries times (the first delivery included) and Nacked each time, it
+// is published to DeadLetterTopic and acked on the original subscription instead of
+// being redelivered again.
+type DLQPolicy struct {
+	MaxDeliveries   uint32
+	DeadLetterTopic string
+}
+
+// InitialPosition selects where a brand-new subscription starts consuming from.
+type InitialPosition int
+
+const (
+	InitialPositionLatest InitialPosition = iota
+	InitialPositionEarliest
+)
+
+// ConsumerOptions configures a Consumer created via Client.Subscribe.
+type ConsumerOptions struct {
+	// Topic is the topic to subscribe to. Exactly one of Topic, Topics or TopicsPattern
+	// must be set.
+	Topic string
+
+	// Topics subscribes to a fixed set of topics, fanning their messages into a single
+	// Receive() stream.
+	Topics []string
+
+	// TopicsPattern subscribes to every topic matching a regex, e.g.
+	// "persistent://tenant/ns/foo-.*". The namespace (tenant/ns) must be a literal
+	// prefix; only the final path segment is matched against the regexp. The consumer
+	// polls the namespace every AutoDiscoveryPeriod to pick up topics created or deleted
+	// after the subscription was created.
+	TopicsPattern string
+
+	// AutoDiscoveryPeriod controls how often TopicsPattern is re-resolved. Defaults to
+	// 60 seconds.
+	AutoDiscoveryPeriod time.Duration
+
+	// SubscriptionName identifies the subscription. Required.
+	SubscriptionName string
+
+	// Type selects the subscription's delivery semantics. Defaults to Exclusive.
+	Type SubscriptionType
+
+	// InitialPosition selects where a new subscription starts reading from.
+	InitialPosition InitialPosition
+
+	// ReceiverQueueSize sets how many messages are prefetched from the broker.
+	ReceiverQueueSize int
+
+	// Properties attaches arbitrary metadata to the subscription.
+	Properties map[string]string
+
+	// Schema, when set, is used by Message.GetSchemaValue to decode messages delivered
+	// to this consumer.
+	Schema Schema
+
+	// NackRedeliveryDelay is how long the broker waits before redelivering a message
+	// passed to Consumer.Nack. Defaults to 1 minute.
+	NackRedeliveryDelay time.Duration
+
+	// DLQ, when set, moves a message to DLQ.DeadLetterTopic instead of redelivering it
+	// again once it has failed DLQ.MaxDeliveries times.
+	DLQ *DLQPolicy
+}
+
+// ConsumerMessage pairs a Message with the Consumer it arrived on. When a Consumer spans
+// more than one topic (ConsumerOptions.Topics/TopicsPattern), Consumer identifies which
+// underlying topic/partition produced Message and must be used to Ack it.
+type ConsumerMessage struct {
+	Consumer Consumer
+	Message  Message
+}
+
+// Consumer consumes messages from one or more topics under a named subscription.
+type Consumer interface {
+	// Topic returns the topic this consumer is subscribed to. For a multi-topic
+	// consumer, it returns the topic of the partition this Consumer value represents.
+	Topic() string
+
+	// Subscription returns the subscription name.
+	Subscription() string
+
+	// Receive blocks until a message is available, ctx is done, or an error occurs. For
+	// a multi-topic/regex consumer, messages from every underlying topic are delivered
+	// on this single call in arrival order.
+	Receive(ctx context.Context) (ConsumerMessage, error)
+
+	// Ack acknowledges a message, marking it as processed.
+	Ack(msg Message) error
+
+	// AckID acknowledges a message by ID. Only valid on a consumer subscribed to exactly
+	// one topic; a multi-topic/regex consumer has no way to tell which topic an ID
+	// belongs to, so it returns an error -- use Ack with the Message from Receive there
+	// instead.
+	AckID(id MessageID) error
+
+	// AckCumulative acknowledges msg and every message that preceded it on the same
+	// topic, so the broker can skip redelivering them to a new or reconnecting consumer.
+	AckCumulative(msg Message) error
+
+	// Nack marks a message as failed to process, asking the broker to redeliver it after
+	// ConsumerOptions.NackRedeliveryDelay. Once a message's redelivery count reaches
+	// ConsumerOptions.DLQ.MaxDeliveries minus one (i.e. it has been delivered
+	// MaxDeliveries times), Nack publishes it to DLQ.DeadLetterTopic and acks it instead
+	// of asking for another redelivery.
+	Nack(msg Message) error
+
+	// ReconsumeLater behaves like Nack, but redelivers msg after delay instead of
+	// ConsumerOptions.NackRedeliveryDelay.
+	ReconsumeLater(msg Message, delay time.Duration) error
+
+	// Seek resets the subscription to start redelivering from id. Only valid on a
+	// consumer subscribed to exactly one topic.
+	Seek(id MessageID) error
+
+	// SeekByTime resets the subscription to start redelivering from the first message
+	// published at or after t. Only valid on a consumer subscribed to exactly one topic.
+	SeekByTime(t time.Time) error
+
+	// Close releases the resources held by the consumer without unsubscribing.
+	Close() error
+}
@@ -0,0 +1,157 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsar
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal/connection"
+)
+
+// Result enumerates the error codes that can be returned by the underlying Pulsar client.
+type Result int
+
+const (
+	Ok Result = iota
+	UnknownError
+	InvalidConfiguration
+	Timeout
+	LookupError
+	ConnectError
+	ReadError
+	AuthenticationError
+	AuthorizationError
+	ErrorGettingAuthenticationData
+	BrokerMetadataError
+	BrokerPersistenceError
+	ChecksumError
+	ConsumerBusy
+	NotConnected
+	AlreadyClosed
+	InvalidMessage
+	ConsumerNotInitialized
+	ProducerNotInitialized
+	ProducerQueueIsFull
+	MessageTooBig
+	TopicNotFound
+	SubscriptionNotFound
+	ConsumerNotFound
+	UnsupportedVersionError
+	TopicTerminated
+	CryptoError
+)
+
+func (r Result) String() string {
+	switch r {
+	case Ok:
+		return "Ok"
+	case InvalidConfiguration:
+		return "InvalidConfiguration"
+	case Timeout:
+		return "Timeout"
+	case LookupError:
+		return "LookupError"
+	case ConnectError:
+		return "ConnectError"
+	case ReadError:
+		return "ReadError"
+	case AuthenticationError:
+		return "AuthenticationError"
+	case AuthorizationError:
+		return "AuthorizationError"
+	case ConsumerBusy:
+		return "ConsumerBusy"
+	case NotConnected:
+		return "NotConnected"
+	case AlreadyClosed:
+		return "AlreadyClosed"
+	case InvalidMessage:
+		return "InvalidMessage"
+	case ProducerQueueIsFull:
+		return "ProducerQueueIsFull"
+	case MessageTooBig:
+		return "MessageTooBig"
+	case TopicNotFound:
+		return "TopicNotFound"
+	case SubscriptionNotFound:
+		return "SubscriptionNotFound"
+	case ConsumerNotFound:
+		return "ConsumerNotFound"
+	case TopicTerminated:
+		return "TopicTerminated"
+	default:
+		return "UnknownError"
+	}
+}
+
+// Error wraps a Result code together with a human-readable description of the failure.
+type Error struct {
+	result Result
+	msg    string
+}
+
+// newError builds an error carrying the given Result code, or nil when result is Ok.
+func newError(result Result, msg string) error {
+	if result == Ok {
+		return nil
+	}
+
+	return &Error{result: result, msg: msg}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.msg, e.result)
+}
+
+// Result returns the error code associated with this error.
+func (e *Error) Result() Result {
+	return e.result
+}
+
+// wrapConnErr translates a failure from pulsar/internal/connection into an *Error carrying
+// the Result code its Kind corresponds to, so callers across a dial, lookup or
+// producer/consumer-create can still recover Result() regardless of which step failed.
+// fallback is used for connection errors whose Kind doesn't map onto a specific Result
+// (e.g. a broker-returned CommandError), and for errors connection didn't produce at all.
+func wrapConnErr(err error, fallback Result) error {
+	if err == nil {
+		return nil
+	}
+
+	var perr *Error
+	if errors.As(err, &perr) {
+		return perr
+	}
+
+	var cerr *connection.Error
+	if errors.As(err, &cerr) {
+		switch cerr.Kind {
+		case connection.KindConnect:
+			return newError(ConnectError, err.Error())
+		case connection.KindLookup:
+			return newError(LookupError, err.Error())
+		case connection.KindTimeout:
+			return newError(Timeout, err.Error())
+		}
+	}
+
+	return newError(fallback, err.Error())
+}
@@ -0,0 +1,116 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsar
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/apache/pulsar-client-go/pkg/auth"
+	"github.com/apache/pulsar-client-go/pulsar/internal/connection"
+)
+
+// client is the pure-Go Client implementation: it owns a connectionPool shared by every
+// producer/consumer/reader it creates, and a lookupService used to resolve topics to the
+// broker that currently owns them.
+type client struct {
+	options ClientOptions
+
+	connectionPool *connection.Pool
+	lookupService  *connection.LookupService
+
+	producerIDCounter uint64 // atomic
+	consumerIDCounter uint64 // atomic
+}
+
+func newClient(options ClientOptions) (Client, error) {
+	if options.URL == "" {
+		return nil, newError(InvalidConfiguration, "URL is required for client")
+	}
+
+	tlsConfig, err := tlsConfigFor(options.URL, options.Authentication)
+	if err != nil {
+		return nil, newError(InvalidConfiguration, err.Error())
+	}
+
+	pool := connection.NewPool(tlsConfig, options.Authentication)
+
+	return &client{
+		options:        options,
+		connectionPool: pool,
+		lookupService:  connection.NewLookupService(pool, options.URL),
+	}, nil
+}
+
+// tlsConfigFor builds the tls.Config a client's connections dial with, or nil to dial
+// plaintext. TLS is enabled either because serviceURL uses the pulsar+ssl scheme or
+// because authentication is an auth.CertificateProvider (currently only
+// NewAuthenticationTLS) -- the two are independent, since bearer-style providers
+// (token/OAuth2/Athenz) authenticate over CommandConnect but still need their AuthData
+// encrypted in transit when the broker is reached over pulsar+ssl.
+func tlsConfigFor(serviceURL string, authentication auth.Provider) (*tls.Config, error) {
+	u, err := url.Parse(serviceURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing service URL %q: %w", serviceURL, err)
+	}
+
+	certProvider, hasCert := authentication.(auth.CertificateProvider)
+	if u.Scheme != "pulsar+ssl" && !hasCert {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if hasCert {
+		cert, err := certProvider.Certificate()
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func (c *client) nextProducerID() uint64 {
+	return atomic.AddUint64(&c.producerIDCounter, 1)
+}
+
+func (c *client) nextConsumerID() uint64 {
+	return atomic.AddUint64(&c.consumerIDCounter, 1)
+}
+
+func (c *client) CreateProducer(options ProducerOptions) (Producer, error) {
+	return newProducer(c, options)
+}
+
+func (c *client) CreateReader(options ReaderOptions) (Reader, error) {
+	return newReader(c, options)
+}
+
+func (c *client) Subscribe(options ConsumerOptions) (Consumer, error) {
+	return newConsumer(c, options)
+}
+
+func (c *client) Close() error {
+	c.connectionPool.Close()
+	return nil
+}
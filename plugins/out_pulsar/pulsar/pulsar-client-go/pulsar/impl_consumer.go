@@ -0,0 +1,699 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal/connection"
+	"github.com/apache/pulsar-client-go/pulsar/internal/pb"
+)
+
+// defaultReceiverQueueSize is how many message permits a partitionConsumer grants the
+// broker up front, when ConsumerOptions/ReaderOptions don't specify one.
+const defaultReceiverQueueSize = 1000
+
+// partitionConsumer owns the subscription and flow control for a single partition (or the
+// whole topic, for an unpartitioned one), and feeds decoded messages into messages.
+type partitionConsumer struct {
+	client *client
+	topic  string
+	id     uint64
+
+	conn *connection.Connection
+
+	queueSize int
+	messages  chan Message
+
+	schema Schema
+}
+
+func newPartitionConsumer(client *client, topic, subscription string, subType pb.SubType, readCompacted bool, initialPosition pb.InitialPosition, consumerID uint64, schema Schema, queueSize int) (*partitionConsumer, error) {
+	if queueSize <= 0 {
+		queueSize = defaultReceiverQueueSize
+	}
+
+	logicalAddr, physicalAddr, err := client.lookupService.Lookup(topic)
+	if err != nil {
+		return nil, wrapConnErr(err, LookupError)
+	}
+
+	conn, err := client.connectionPool.GetConnection(logicalAddr, physicalAddr)
+	if err != nil {
+		return nil, wrapConnErr(err, ConnectError)
+	}
+
+	pc := &partitionConsumer{
+		client:    client,
+		topic:     topic,
+		id:        consumerID,
+		conn:      conn,
+		queueSize: queueSize,
+		messages:  make(chan Message, queueSize),
+		schema:    schema,
+	}
+
+	var schemaPb *pb.Schema
+	if schema != nil {
+		info := schema.Schema()
+		schemaPb = &pb.Schema{Name: info.Name, SchemaData: info.Schema, Type: int32(info.Type), Properties: info.Properties}
+	}
+
+	_, err = conn.SendRequest(func(requestID uint64) *pb.BaseCommand {
+		return &pb.BaseCommand{
+			Type: pb.Type_SUBSCRIBE,
+			Subscribe: &pb.CommandSubscribe{
+				Topic:           topic,
+				Subscription:    subscription,
+				SubType:         subType,
+				ConsumerId:      consumerID,
+				RequestId:       requestID,
+				Schema:          schemaPb,
+				ReadCompacted:   readCompacted,
+				InitialPosition: initialPosition,
+			},
+		}
+	}, nil, nil)
+	if err != nil {
+		return nil, wrapConnErr(err, UnknownError)
+	}
+
+	conn.RegisterConsumer(consumerID, pc.handleMessage)
+	conn.OnConsumerClosed(consumerID, func() {
+		// The broker closed this one consumer; no new CommandMessage will arrive for
+		// consumerID, but every other producer/consumer sharing conn keeps working.
+	})
+
+	// Grant the broker permits for the whole receiver queue up front.
+	if err := pc.flow(uint32(queueSize)); err != nil {
+		return nil, err
+	}
+
+	return pc, nil
+}
+
+func (pc *partitionConsumer) handleMessage(_ uint64, cmd *pb.CommandMessage, metadata *pb.MessageMetadata, payload []byte) {
+	var publishTime time.Time
+	var key string
+	var properties map[string]string
+	if metadata != nil {
+		if metadata.PublishTime > 0 {
+			publishTime = time.UnixMilli(int64(metadata.PublishTime))
+		}
+		key = metadata.PartitionKey
+		properties = metadata.Properties
+	}
+
+	pc.messages <- &message{
+		topic:           pc.topic,
+		payload:         payload,
+		id:              messageIDFromPb(cmd.MessageId),
+		schema:          pc.schema,
+		key:             key,
+		properties:      properties,
+		publishTime:     publishTime,
+		redeliveryCount: cmd.RedeliveryCount,
+	}
+}
+
+func (pc *partitionConsumer) flow(permits uint32) error {
+	return pc.conn.SendCommand(&pb.BaseCommand{
+		Type: pb.Type_FLOW,
+		Flow: &pb.CommandFlow{
+			ConsumerId:     pc.id,
+			MessagePermits: permits,
+		},
+	}, nil, nil)
+}
+
+func (pc *partitionConsumer) ack(id MessageID, ackType pb.AckType) error {
+	mid, ok := id.(messageID)
+	if !ok {
+		return newError(InvalidMessage, "message ID was not produced by this client")
+	}
+
+	err := pc.conn.SendCommand(&pb.BaseCommand{
+		Type: pb.Type_ACK,
+		Ack: &pb.CommandAck{
+			ConsumerId: pc.id,
+			AckType:    ackType,
+			MessageId: []*pb.MessageIdData{{
+				LedgerId:   mid.ledgerID,
+				EntryId:    mid.entryID,
+				Partition:  mid.partition,
+				BatchIndex: mid.batchIndex,
+			}},
+		},
+	}, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	// Acking frees up a slot in the receiver queue, so grant the broker one more permit.
+	return pc.flow(1)
+}
+
+// redeliver asks the broker to redeliver ids on this subscription, used by Consumer.Nack
+// and Consumer.ReconsumeLater once their redelivery delay elapses.
+func (pc *partitionConsumer) redeliver(ids []MessageID) error {
+	pbIDs := make([]*pb.MessageIdData, 0, len(ids))
+	for _, id := range ids {
+		mid, ok := id.(messageID)
+		if !ok {
+			continue
+		}
+		pbIDs = append(pbIDs, &pb.MessageIdData{
+			LedgerId:   mid.ledgerID,
+			EntryId:    mid.entryID,
+			Partition:  mid.partition,
+			BatchIndex: mid.batchIndex,
+		})
+	}
+
+	return pc.conn.SendCommand(&pb.BaseCommand{
+		Type: pb.Type_REDELIVER_UNACKNOWLEDGED_MESSAGES,
+		RedeliverUnacknowledged: &pb.CommandRedeliverUnacknowledgedMessages{
+			ConsumerId: pc.id,
+			MessageIds: pbIDs,
+		},
+	}, nil, nil)
+}
+
+// seek resets this subscription's position to id.
+func (pc *partitionConsumer) seek(id MessageID) error {
+	mid, ok := id.(messageID)
+	if !ok {
+		return newError(InvalidMessage, "message ID was not produced by this client")
+	}
+
+	_, err := pc.conn.SendRequest(func(requestID uint64) *pb.BaseCommand {
+		return &pb.BaseCommand{
+			Type: pb.Type_SEEK,
+			Seek: &pb.CommandSeek{
+				ConsumerId: pc.id,
+				RequestId:  requestID,
+				MessageId: &pb.MessageIdData{
+					LedgerId:   mid.ledgerID,
+					EntryId:    mid.entryID,
+					Partition:  mid.partition,
+					BatchIndex: mid.batchIndex,
+				},
+			},
+		}
+	}, nil, nil)
+	return err
+}
+
+// seekByTime resets this subscription's position to the first message published at or
+// after t.
+func (pc *partitionConsumer) seekByTime(t time.Time) error {
+	_, err := pc.conn.SendRequest(func(requestID uint64) *pb.BaseCommand {
+		return &pb.BaseCommand{
+			Type: pb.Type_SEEK,
+			Seek: &pb.CommandSeek{
+				ConsumerId:         pc.id,
+				RequestId:          requestID,
+				MessagePublishTime: t.UnixMilli(),
+			},
+		}
+	}, nil, nil)
+	return err
+}
+
+func (pc *partitionConsumer) close() error {
+	defer pc.conn.UnregisterConsumer(pc.id)
+
+	_, err := pc.conn.SendRequest(func(requestID uint64) *pb.BaseCommand {
+		return &pb.BaseCommand{
+			Type: pb.Type_CLOSE_CONSUMER,
+			CloseConsumer: &pb.CommandCloseConsumer{
+				ConsumerId: pc.id,
+				RequestId:  requestID,
+			},
+		}
+	}, nil, nil)
+	return err
+}
+
+/// Consumer
+
+// consumer subscribes to one or more topics (Topic, Topics, or every topic matching
+// TopicsPattern) under a single subscription name. Each topic gets its own
+// partitionConsumer; a topicConsumer wraps one of them to satisfy the single-topic
+// Consumer interface for Ack/Close, while messages from every topic are fanned into one
+// ordered-by-arrival ConsumerMessage channel.
+type consumer struct {
+	client *client
+
+	subscription    string
+	subType         pb.SubType
+	readCompacted   bool
+	initialPosition pb.InitialPosition
+	schema          Schema
+	queueSize       int
+
+	adminHTTPURL  string
+	topicsPattern string
+	discoveryStop chan struct{}
+
+	nackRedeliveryDelay time.Duration
+	dlqPolicy           *DLQPolicy
+	dlqProducer         Producer
+
+	mu       sync.Mutex
+	byTopic  map[string]*topicConsumer
+	messages chan ConsumerMessage
+	ring     *keyShardRing // non-nil only when subType == pb.SubType_KEY_SHARED
+}
+
+// topicConsumer is the per-topic Consumer handed back in each ConsumerMessage, so callers
+// can Ack against the right partitionConsumer regardless of how many topics the overall
+// subscription spans. It also holds the pending Nack/ReconsumeLater redelivery timers a
+// keyShardRing has assigned to this topic.
+type topicConsumer struct {
+	parent *consumer
+	pc     *partitionConsumer
+	stop   chan struct{}
+
+	pendingMu        sync.Mutex
+	pendingRedeliver map[string]*time.Timer
+}
+
+func (tc *topicConsumer) Topic() string            { return tc.pc.topic }
+func (tc *topicConsumer) Subscription() string     { return tc.parent.subscription }
+func (tc *topicConsumer) Ack(msg Message) error    { return tc.pc.ack(msg.ID(), pb.AckType_INDIVIDUAL) }
+func (tc *topicConsumer) AckID(id MessageID) error { return tc.pc.ack(id, pb.AckType_INDIVIDUAL) }
+func (tc *topicConsumer) AckCumulative(msg Message) error {
+	return tc.pc.ack(msg.ID(), pb.AckType_CUMULATIVE)
+}
+func (tc *topicConsumer) Seek(id MessageID) error      { return tc.pc.seek(id) }
+func (tc *topicConsumer) SeekByTime(t time.Time) error { return tc.pc.seekByTime(t) }
+
+func (tc *topicConsumer) Nack(msg Message) error {
+	tc.parent.scheduleRedelivery(tc, msg, tc.parent.nackRedeliveryDelay)
+	return nil
+}
+
+func (tc *topicConsumer) ReconsumeLater(msg Message, delay time.Duration) error {
+	tc.parent.scheduleRedelivery(tc, msg, delay)
+	return nil
+}
+
+func (tc *topicConsumer) Close() error {
+	close(tc.stop)
+
+	tc.pendingMu.Lock()
+	for _, timer := range tc.pendingRedeliver {
+		timer.Stop()
+	}
+	tc.pendingRedeliver = nil
+	tc.pendingMu.Unlock()
+
+	return tc.pc.close()
+}
+
+func (tc *topicConsumer) Receive(ctx context.Context) (ConsumerMessage, error) {
+	return tc.parent.Receive(ctx)
+}
+
+func newConsumer(client *client, options ConsumerOptions) (Consumer, error) {
+	if options.SubscriptionName == "" {
+		return nil, newError(InvalidConfiguration, "SubscriptionName is required when subscribing")
+	}
+
+	topics, err := resolveTopics(adminHTTPURLFromServiceURL(client.options.URL), options.Topic, options.Topics, options.TopicsPattern)
+	if err != nil {
+		return nil, newError(InvalidConfiguration, err.Error())
+	}
+
+	var subType pb.SubType
+	switch options.Type {
+	case Shared:
+		subType = pb.SubType_SHARED
+	case Failover:
+		subType = pb.SubType_FAILOVER
+	case KeyShared:
+		subType = pb.SubType_KEY_SHARED
+	default:
+		subType = pb.SubType_EXCLUSIVE
+	}
+
+	initialPosition := pb.InitialPosition_Latest
+	if options.InitialPosition == InitialPositionEarliest {
+		initialPosition = pb.InitialPosition_Earliest
+	}
+
+	queueSize := options.ReceiverQueueSize
+	if queueSize <= 0 {
+		queueSize = defaultReceiverQueueSize
+	}
+
+	nackRedeliveryDelay := options.NackRedeliveryDelay
+	if nackRedeliveryDelay <= 0 {
+		nackRedeliveryDelay = defaultNackRedeliveryDelay
+	}
+
+	c := &consumer{
+		client:              client,
+		subscription:        options.SubscriptionName,
+		subType:             subType,
+		initialPosition:     initialPosition,
+		schema:              options.Schema,
+		queueSize:           queueSize,
+		adminHTTPURL:        adminHTTPURLFromServiceURL(client.options.URL),
+		topicsPattern:       options.TopicsPattern,
+		nackRedeliveryDelay: nackRedeliveryDelay,
+		dlqPolicy:           options.DLQ,
+		byTopic:             make(map[string]*topicConsumer),
+		messages:            make(chan ConsumerMessage, queueSize*len(topics)),
+	}
+
+	if options.DLQ != nil {
+		dlqProducer, err := client.CreateProducer(ProducerOptions{Topic: options.DLQ.DeadLetterTopic})
+		if err != nil {
+			return nil, wrapConnErr(err, UnknownError)
+		}
+		c.dlqProducer = dlqProducer
+	}
+
+	for _, topic := range topics {
+		if err := c.addTopic(topic); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+
+	if options.TopicsPattern != "" {
+		period := options.AutoDiscoveryPeriod
+		if period <= 0 {
+			period = defaultAutoDiscoveryPeriod
+		}
+		c.discoveryStop = make(chan struct{})
+		go c.pollDiscovery(period)
+	}
+
+	return c, nil
+}
+
+func (c *consumer) addTopic(topic string) error {
+	pc, err := newPartitionConsumer(c.client, topic, c.subscription, c.subType, c.readCompacted, c.initialPosition,
+		c.client.nextConsumerID(), c.schema, c.queueSize)
+	if err != nil {
+		return err
+	}
+
+	tc := &topicConsumer{parent: c, pc: pc, stop: make(chan struct{})}
+
+	c.mu.Lock()
+	c.byTopic[topic] = tc
+	c.rebuildRingLocked()
+	c.mu.Unlock()
+
+	go tc.pump()
+	return nil
+}
+
+// rebuildRingLocked recomputes c.ring from the topics currently in c.byTopic. The caller
+// must hold c.mu. It's a no-op unless the subscription is KeyShared.
+func (c *consumer) rebuildRingLocked() {
+	if c.subType != pb.SubType_KEY_SHARED {
+		return
+	}
+
+	topics := make([]string, 0, len(c.byTopic))
+	for topic := range c.byTopic {
+		topics = append(topics, topic)
+	}
+	c.ring = newKeyShardRing(topics)
+}
+
+func (tc *topicConsumer) pump() {
+	for {
+		select {
+		case <-tc.stop:
+			return
+		case msg := <-tc.pc.messages:
+			tc.parent.messages <- ConsumerMessage{Consumer: tc, Message: msg}
+		}
+	}
+}
+
+// pollDiscovery re-resolves c.topicsPattern every period, adding partitionConsumers for
+// newly matching topics and tearing down ones for topics that no longer match.
+func (c *consumer) pollDiscovery(period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	namespace, re, err := parseTopicsPattern(c.topicsPattern)
+	if err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-c.discoveryStop:
+			return
+		case <-ticker.C:
+			topics, err := discoverTopics(c.adminHTTPURL, namespace, re)
+			if err != nil {
+				continue
+			}
+
+			current := make(map[string]bool, len(topics))
+			for _, t := range topics {
+				current[t] = true
+				c.mu.Lock()
+				_, exists := c.byTopic[t]
+				c.mu.Unlock()
+				if !exists {
+					_ = c.addTopic(t)
+				}
+			}
+
+			c.mu.Lock()
+			for t, tc := range c.byTopic {
+				if !current[t] {
+					delete(c.byTopic, t)
+					go tc.Close()
+				}
+			}
+			c.rebuildRingLocked()
+			c.mu.Unlock()
+		}
+	}
+}
+
+// Topic returns the topic of an arbitrary underlying partition consumer. For a
+// multi-topic subscription, prefer the Consumer carried on each ConsumerMessage.
+func (c *consumer) Topic() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for topic := range c.byTopic {
+		return topic
+	}
+	return ""
+}
+
+func (c *consumer) Subscription() string { return c.subscription }
+
+func (c *consumer) Receive(ctx context.Context) (ConsumerMessage, error) {
+	select {
+	case <-ctx.Done():
+		return ConsumerMessage{}, ctx.Err()
+	case cm := <-c.messages:
+		return cm, nil
+	}
+}
+
+func (c *consumer) Ack(msg Message) error {
+	tc, err := c.topicConsumerFor(msg)
+	if err != nil {
+		return err
+	}
+	return tc.Ack(msg)
+}
+
+func (c *consumer) AckCumulative(msg Message) error {
+	tc, err := c.topicConsumerFor(msg)
+	if err != nil {
+		return err
+	}
+	return tc.AckCumulative(msg)
+}
+
+func (c *consumer) Nack(msg Message) error {
+	tc, err := c.topicConsumerFor(msg)
+	if err != nil {
+		return err
+	}
+	return tc.Nack(msg)
+}
+
+func (c *consumer) ReconsumeLater(msg Message, delay time.Duration) error {
+	tc, err := c.topicConsumerFor(msg)
+	if err != nil {
+		return err
+	}
+	return tc.ReconsumeLater(msg, delay)
+}
+
+// AckID, Seek and SeekByTime have no topic to resolve against other than the one
+// (singular) topic this consumer spans; singleTopicConsumer reports that clearly instead
+// of guessing for a multi-topic/regex subscription.
+func (c *consumer) AckID(id MessageID) error {
+	tc, err := c.singleTopicConsumer("AckID")
+	if err != nil {
+		return err
+	}
+	return tc.AckID(id)
+}
+
+func (c *consumer) Seek(id MessageID) error {
+	tc, err := c.singleTopicConsumer("Seek")
+	if err != nil {
+		return err
+	}
+	return tc.Seek(id)
+}
+
+func (c *consumer) SeekByTime(t time.Time) error {
+	tc, err := c.singleTopicConsumer("SeekByTime")
+	if err != nil {
+		return err
+	}
+	return tc.SeekByTime(t)
+}
+
+func (c *consumer) topicConsumerFor(msg Message) (*topicConsumer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tc, ok := c.byTopic[msg.Topic()]
+	if !ok {
+		return nil, newError(InvalidMessage, "message's topic is not part of this consumer")
+	}
+	return tc, nil
+}
+
+func (c *consumer) singleTopicConsumer(op string) (*topicConsumer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.byTopic) != 1 {
+		return nil, newError(InvalidConfiguration,
+			fmt.Sprintf("%s requires a single-topic consumer; use Ack/Nack with the Message from Receive for multi-topic consumers", op))
+	}
+	for _, tc := range c.byTopic {
+		return tc, nil
+	}
+	return nil, newError(InvalidConfiguration, op+" requires a single-topic consumer")
+}
+
+// scheduleRedelivery arranges for msg to be redelivered -- or, once its redelivery count
+// reaches DLQPolicy.MaxDeliveries, moved to the dead letter topic -- after delay. The
+// pending timer lives on whichever topicConsumer the keyShardRing assigns msg's key to,
+// so repeated Nacks for the same key collapse onto a single timer even if that key
+// happens to appear on more than one of this consumer's topics; the actual ack/redeliver
+// commands always go through origin, the topicConsumer msg actually arrived on, since
+// that's the only one with a matching broker subscription.
+func (c *consumer) scheduleRedelivery(origin *topicConsumer, msg Message, delay time.Duration) {
+	owner := origin
+	if o := c.ringOwner(msg.Key()); o != nil {
+		owner = o
+	}
+
+	key := msg.Key()
+	if key == "" {
+		key = string(msg.ID().Serialize())
+	}
+
+	owner.pendingMu.Lock()
+	if owner.pendingRedeliver == nil {
+		owner.pendingRedeliver = make(map[string]*time.Timer)
+	}
+	if timer, exists := owner.pendingRedeliver[key]; exists {
+		timer.Stop()
+	}
+	owner.pendingRedeliver[key] = time.AfterFunc(delay, func() {
+		owner.pendingMu.Lock()
+		delete(owner.pendingRedeliver, key)
+		owner.pendingMu.Unlock()
+		c.handleRedeliveryTimeout(origin, msg)
+	})
+	owner.pendingMu.Unlock()
+}
+
+// ringOwner returns the topicConsumer c.ring assigns key to, or nil if this isn't a
+// KeyShared subscription, key is empty, or the assigned topic is no longer present.
+func (c *consumer) ringOwner(key string) *topicConsumer {
+	if key == "" {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ring == nil {
+		return nil
+	}
+	return c.byTopic[c.ring.owner(key)]
+}
+
+// handleRedeliveryTimeout fires once a Nack/ReconsumeLater's delay has elapsed: it moves
+// msg to the dead letter topic if DLQPolicy.MaxDeliveries has been reached, otherwise
+// asks the broker to redeliver it.
+func (c *consumer) handleRedeliveryTimeout(origin *topicConsumer, msg Message) {
+	if c.dlqPolicy != nil && msg.RedeliveryCount()+1 >= c.dlqPolicy.MaxDeliveries {
+		err := c.dlqProducer.Send(context.Background(), ProducerMessage{
+			Payload:    msg.Payload(),
+			Key:        msg.Key(),
+			Properties: msg.Properties(),
+		})
+		if err == nil {
+			_ = origin.pc.ack(msg.ID(), pb.AckType_INDIVIDUAL)
+			return
+		}
+		// Fall through and ask for an ordinary redelivery rather than lose the message.
+	}
+
+	_ = origin.pc.redeliver([]MessageID{msg.ID()})
+}
+
+func (c *consumer) Close() error {
+	if c.discoveryStop != nil {
+		close(c.discoveryStop)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var firstErr error
+	for _, tc := range c.byTopic {
+		if err := tc.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if c.dlqProducer != nil {
+		if err := c.dlqProducer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
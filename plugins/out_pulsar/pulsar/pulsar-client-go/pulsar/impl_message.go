@@ -0,0 +1,93 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal/pb"
+)
+
+// messageID is the MessageID implementation returned for messages actually delivered by a
+// broker, as opposed to the EarliestMessage/LatestMessage sentinels.
+type messageID struct {
+	ledgerID   uint64
+	entryID    uint64
+	partition  int32
+	batchIndex int32
+}
+
+func (id messageID) Serialize() []byte {
+	return []byte(fmt.Sprintf("%d:%d:%d:%d", id.ledgerID, id.entryID, id.partition, id.batchIndex))
+}
+
+func messageIDFromPb(m *pb.MessageIdData) MessageID {
+	return messageID{
+		ledgerID:   m.LedgerId,
+		entryID:    m.EntryId,
+		partition:  m.Partition,
+		batchIndex: m.BatchIndex,
+	}
+}
+
+// message is the Message implementation produced by the native reader/consumer
+// implementations.
+type message struct {
+	topic           string
+	properties      map[string]string
+	payload         []byte
+	id              MessageID
+	publishTime     time.Time
+	key             string
+	redeliveryCount uint32
+
+	schema Schema
+}
+
+func (m *message) Topic() string                 { return m.topic }
+func (m *message) Properties() map[string]string { return m.properties }
+func (m *message) Payload() []byte               { return m.payload }
+func (m *message) ID() MessageID                 { return m.id }
+func (m *message) PublishTime() time.Time        { return m.publishTime }
+func (m *message) Key() string                   { return m.key }
+func (m *message) RedeliveryCount() uint32       { return m.redeliveryCount }
+
+func (m *message) GetSchemaValue(v interface{}) error {
+	if m.schema == nil {
+		return &Error{result: InvalidConfiguration, msg: "no schema configured for this message"}
+	}
+	return m.schema.Decode(m.payload, v)
+}
+
+// encodePayload converts a ProducerMessage into the bytes to send on the wire, encoding
+// Value through schema when Payload isn't set directly.
+func encodePayload(msg ProducerMessage, schema Schema) ([]byte, error) {
+	if msg.Payload != nil {
+		return msg.Payload, nil
+	}
+	if msg.Value == nil {
+		return nil, nil
+	}
+	if schema == nil {
+		return nil, &Error{result: InvalidConfiguration, msg: "message has a Value but producer has no Schema configured"}
+	}
+	return schema.Encode(msg.Value)
+}
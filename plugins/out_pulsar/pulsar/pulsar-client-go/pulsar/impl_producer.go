@@ -0,0 +1,457 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsar
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal/connection"
+	"github.com/apache/pulsar-client-go/pulsar/internal/pb"
+)
+
+// sendQueueSize is how many assigned-but-not-yet-written-to-the-wire sends a
+// partitionProducer will buffer before sendAsync blocks the caller.
+const sendQueueSize = 1000
+
+const (
+	initialReconnectBackoff = 100 * time.Millisecond
+	maxReconnectBackoff     = 30 * time.Second
+)
+
+// pendingSend tracks a message that has been assigned a sequence ID but not yet
+// acknowledged by the broker. It keeps the already-encoded metadata/payload around so that
+// a reconnect can replay the exact same bytes without re-running the caller's encoder.
+type pendingSend struct {
+	seqID    uint64
+	message  ProducerMessage
+	metadata *pb.MessageMetadata
+	payload  []byte
+	callback func(ProducerMessage, error)
+}
+
+// partitionProducer owns the connection and send queue for a single partition (or the
+// whole topic, for an unpartitioned one). A single goroutine (run) owns writing sends to
+// the wire, so producer.Send/SendAsync never block on network I/O themselves; on
+// disconnect it reconnects with backoff and replays every still-unacknowledged send in
+// sequence-ID order, instead of failing them outright. Batching multiple messages into one
+// CommandSend is not implemented -- every sendAsync call still writes its own frame.
+type partitionProducer struct {
+	client  *client
+	topic   string
+	id      uint64
+	options ProducerOptions
+
+	connMu sync.RWMutex
+	conn   *connection.Connection
+	name   string
+
+	sequenceID uint64 // atomic, next sequence ID to assign
+
+	pendingMu sync.Mutex
+	pending   map[uint64]*pendingSend
+
+	sendCh chan uint64 // sequence IDs waiting to be written to the wire, drained by run
+
+	reconnectMu  sync.Mutex
+	reconnecting bool
+
+	closeOnce sync.Once
+	closed    chan struct{}
+
+	schema Schema
+}
+
+func newPartitionProducer(client *client, topic string, producerID uint64, options ProducerOptions) (*partitionProducer, error) {
+	p := &partitionProducer{
+		client:  client,
+		topic:   topic,
+		id:      producerID,
+		options: options,
+		pending: make(map[uint64]*pendingSend),
+		sendCh:  make(chan uint64, sendQueueSize),
+		closed:  make(chan struct{}),
+		schema:  options.Schema,
+	}
+
+	if err := p.connect(); err != nil {
+		return nil, err
+	}
+
+	go p.run()
+
+	return p, nil
+}
+
+// connect performs the lookup/CommandProducer handshake and installs p's connection-level
+// handlers. It's used both to establish the initial connection and, by reconnectLoop, to
+// re-establish one after the broker or the TCP connection itself drops.
+func (p *partitionProducer) connect() error {
+	logicalAddr, physicalAddr, err := p.client.lookupService.Lookup(p.topic)
+	if err != nil {
+		return wrapConnErr(err, LookupError)
+	}
+
+	conn, err := p.client.connectionPool.GetConnection(logicalAddr, physicalAddr)
+	if err != nil {
+		return wrapConnErr(err, ConnectError)
+	}
+
+	var schemaPb *pb.Schema
+	if p.options.Schema != nil {
+		info := p.options.Schema.Schema()
+		schemaPb = &pb.Schema{Name: info.Name, SchemaData: info.Schema, Type: int32(info.Type), Properties: info.Properties}
+	}
+
+	resp, err := conn.SendRequest(func(requestID uint64) *pb.BaseCommand {
+		return &pb.BaseCommand{
+			Type: pb.Type_PRODUCER,
+			Producer: &pb.CommandProducer{
+				Topic:      p.topic,
+				ProducerId: p.id,
+				RequestId:  requestID,
+				Properties: p.options.Properties,
+				Schema:     schemaPb,
+			},
+		}
+	}, nil, nil)
+	if err != nil {
+		return wrapConnErr(err, UnknownError)
+	}
+
+	name := resp.ProducerSuccess.ProducerName
+	if p.options.Name != "" {
+		name = p.options.Name
+	}
+
+	conn.RegisterProducer(p.id, func(_, sequenceID uint64) {
+		p.onSendReceipt(sequenceID)
+	})
+	conn.OnClosed(func(err error) {
+		p.handleDisconnect(err)
+	})
+	conn.OnProducerClosed(p.id, func() {
+		p.handleDisconnect(newError(NotConnected, "producer closed by broker"))
+	})
+
+	p.connMu.Lock()
+	p.conn = conn
+	p.name = name
+	p.connMu.Unlock()
+
+	return nil
+}
+
+// handleDisconnect is called from a connection handler when this producer's connection
+// goes away, either because the whole TCP connection closed or because the broker sent
+// CommandCloseProducer for this producer alone. It starts a reconnect loop unless the
+// producer itself has already been explicitly Close()d, in which case there is nothing to
+// reconnect for.
+func (p *partitionProducer) handleDisconnect(err error) {
+	select {
+	case <-p.closed:
+		return
+	default:
+	}
+
+	p.reconnectMu.Lock()
+	if p.reconnecting {
+		p.reconnectMu.Unlock()
+		return
+	}
+	p.reconnecting = true
+	p.reconnectMu.Unlock()
+
+	go p.reconnectLoop()
+}
+
+// reconnectLoop retries connect with exponential backoff until it succeeds or the
+// producer is closed, then replays every message still awaiting a send receipt.
+func (p *partitionProducer) reconnectLoop() {
+	backoff := initialReconnectBackoff
+	for {
+		select {
+		case <-p.closed:
+			return
+		default:
+		}
+
+		if err := p.connect(); err == nil {
+			p.reconnectMu.Lock()
+			p.reconnecting = false
+			p.reconnectMu.Unlock()
+			p.replayPending()
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-p.closed:
+			return
+		}
+		backoff *= 2
+		if backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
+	}
+}
+
+// replayPending re-queues every send still awaiting a receipt, in sequence-ID order, onto
+// the new connection. Sends made concurrently with a reconnect can interleave with this
+// replay, so strict wire ordering isn't guaranteed across a reconnect -- only that no
+// pending message is silently dropped.
+func (p *partitionProducer) replayPending() {
+	p.pendingMu.Lock()
+	seqIDs := make([]uint64, 0, len(p.pending))
+	for seqID := range p.pending {
+		seqIDs = append(seqIDs, seqID)
+	}
+	p.pendingMu.Unlock()
+
+	sort.Slice(seqIDs, func(i, j int) bool { return seqIDs[i] < seqIDs[j] })
+	for _, seqID := range seqIDs {
+		p.sendCh <- seqID
+	}
+}
+
+func (p *partitionProducer) sendAsync(ctx context.Context, msg ProducerMessage, callback func(ProducerMessage, error)) {
+	payload, err := encodePayload(msg, p.schema)
+	if err != nil {
+		go callback(msg, err)
+		return
+	}
+
+	seqID := atomic.AddUint64(&p.sequenceID, 1)
+
+	var eventTime uint64
+	if msg.EventTime != nil {
+		eventTime = uint64(msg.EventTime.UnixMilli())
+	}
+
+	send := &pendingSend{
+		seqID:   seqID,
+		message: msg,
+		metadata: &pb.MessageMetadata{
+			ProducerName: p.name,
+			SequenceId:   seqID,
+			PublishTime:  uint64(time.Now().UnixMilli()),
+			Properties:   msg.Properties,
+			PartitionKey: msg.Key,
+			EventTime:    eventTime,
+		},
+		payload:  payload,
+		callback: callback,
+	}
+
+	p.pendingMu.Lock()
+	p.pending[seqID] = send
+	p.pendingMu.Unlock()
+
+	p.sendCh <- seqID
+}
+
+// run is the single goroutine that owns writing this partitionProducer's sends to the
+// wire, draining sendCh in order.
+func (p *partitionProducer) run() {
+	for {
+		select {
+		case seqID := <-p.sendCh:
+			p.doSend(seqID)
+		case <-p.closed:
+			return
+		}
+	}
+}
+
+// doSend writes one already-assigned send to the current connection. A write failure
+// triggers a reconnect instead of failing the send; the message stays in pending and is
+// replayed once reconnectLoop re-establishes the connection.
+func (p *partitionProducer) doSend(seqID uint64) {
+	p.pendingMu.Lock()
+	send, ok := p.pending[seqID]
+	p.pendingMu.Unlock()
+	if !ok {
+		// Already acknowledged (or failed by an explicit Close) since being queued.
+		return
+	}
+
+	p.connMu.RLock()
+	conn := p.conn
+	p.connMu.RUnlock()
+
+	err := conn.SendCommand(&pb.BaseCommand{
+		Type: pb.Type_SEND,
+		Send: &pb.CommandSend{
+			ProducerId: p.id,
+			SequenceId: seqID,
+		},
+	}, send.metadata, send.payload)
+	if err != nil {
+		p.handleDisconnect(err)
+	}
+}
+
+// onSendReceipt is invoked by the connection's read loop (wired up by connect) when a
+// CommandSendReceipt arrives.
+func (p *partitionProducer) onSendReceipt(seqID uint64) {
+	p.pendingMu.Lock()
+	send, ok := p.pending[seqID]
+	if ok {
+		delete(p.pending, seqID)
+	}
+	p.pendingMu.Unlock()
+
+	if ok {
+		send.callback(send.message, nil)
+	}
+}
+
+// failPending fails every send still awaiting a receipt, used only when the producer
+// itself is explicitly closed -- a connection-level disconnect instead goes through
+// handleDisconnect/reconnectLoop so pending sends are replayed rather than failed.
+func (p *partitionProducer) failPending(err error) {
+	p.pendingMu.Lock()
+	pending := p.pending
+	p.pending = make(map[uint64]*pendingSend)
+	p.pendingMu.Unlock()
+
+	for _, send := range pending {
+		send.callback(send.message, err)
+	}
+}
+
+func (p *partitionProducer) close() error {
+	p.closeOnce.Do(func() {
+		close(p.closed)
+	})
+	p.failPending(newError(AlreadyClosed, "producer closed"))
+
+	p.connMu.RLock()
+	conn := p.conn
+	p.connMu.RUnlock()
+	defer conn.UnregisterProducer(p.id)
+
+	_, err := conn.SendRequest(func(requestID uint64) *pb.BaseCommand {
+		return &pb.BaseCommand{
+			Type: pb.Type_CLOSE_PRODUCER,
+			CloseProducer: &pb.CommandCloseProducer{
+				ProducerId: p.id,
+				RequestId:  requestID,
+			},
+		}
+	}, nil, nil)
+	return err
+}
+
+/// Producer
+
+// producer fans messages out across the partitions of options.Topic, assigning a
+// partition per MessageRouter (or round-robin when none is set; Key is not hashed).
+type producer struct {
+	client     *client
+	topic      string
+	name       string
+	partitions []*partitionProducer
+	router     MessageRouter
+
+	nextPartition uint32 // atomic, round-robin cursor
+}
+
+func newProducer(client *client, options ProducerOptions) (Producer, error) {
+	if options.Topic == "" {
+		return nil, newError(InvalidConfiguration, "topic is required when creating producer")
+	}
+
+	// TODO(partitioned topics): query the admin lookup for partition count and spin up
+	// one partitionProducer per partition. Until then, every topic is treated as having
+	// a single partition, which is correct for non-partitioned topics.
+	partitionProd, err := newPartitionProducer(client, options.Topic, client.nextProducerID(), options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &producer{
+		client:     client,
+		topic:      options.Topic,
+		name:       partitionProd.name,
+		partitions: []*partitionProducer{partitionProd},
+		router:     options.MessageRouter,
+	}, nil
+}
+
+func (p *producer) Topic() string { return p.topic }
+func (p *producer) Name() string  { return p.name }
+
+func (p *producer) choosePartition(msg ProducerMessage) *partitionProducer {
+	if len(p.partitions) == 1 {
+		return p.partitions[0]
+	}
+
+	if p.router != nil {
+		idx := p.router(&message{payload: msg.Payload, key: msg.Key}, &topicMetadata{len(p.partitions)})
+		return p.partitions[idx%len(p.partitions)]
+	}
+
+	idx := atomic.AddUint32(&p.nextPartition, 1)
+	return p.partitions[int(idx)%len(p.partitions)]
+}
+
+func (p *producer) Send(ctx context.Context, msg ProducerMessage) error {
+	c := make(chan error, 1)
+	p.SendAsync(ctx, msg, func(_ ProducerMessage, err error) { c <- err })
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-c:
+		return err
+	}
+}
+
+func (p *producer) SendAsync(ctx context.Context, msg ProducerMessage, callback func(ProducerMessage, error)) {
+	p.choosePartition(msg).sendAsync(ctx, msg, callback)
+}
+
+func (p *producer) Close() error {
+	c := make(chan error, 1)
+	p.CloseAsync(func(err error) { c <- err })
+	return <-c
+}
+
+func (p *producer) CloseAsync(callback func(error)) {
+	go func() {
+		var firstErr error
+		for _, pp := range p.partitions {
+			if err := pp.close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		callback(firstErr)
+	}()
+}
+
+type topicMetadata struct {
+	numPartitions int
+}
+
+func (tm *topicMetadata) NumPartitions() int { return tm.numPartitions }
@@ -0,0 +1,255 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal/pb"
+)
+
+// reader is a Reader backed by one non-shared, non-durable subscription per topic: the
+// broker treats each like any other consumer, but the reader acks every message as soon
+// as it's handed to the caller so the subscriptions never retain backlog. When reading
+// more than one topic (ReaderOptions.Topics/TopicsPattern), messages from every
+// underlying topic are fanned into a single Next() stream, ordered by arrival.
+type reader struct {
+	client *client
+
+	readCompacted  bool
+	startMessageID MessageID
+	schema         Schema
+	queueSize      int
+
+	adminHTTPURL  string
+	topicsPattern string
+	discoveryStop chan struct{}
+
+	mu      sync.Mutex
+	byTopic map[string]*readerTopic
+
+	messages chan Message
+}
+
+// readerTopic is the partitionConsumer backing one of a multi-topic reader's topics.
+type readerTopic struct {
+	pc   *partitionConsumer
+	stop chan struct{}
+}
+
+func newReader(client *client, options ReaderOptions) (Reader, error) {
+	if options.StartMessageID == nil {
+		return nil, newError(InvalidConfiguration, "StartMessageID is required when creating reader")
+	}
+
+	adminHTTPURL := adminHTTPURLFromServiceURL(client.options.URL)
+
+	topics, err := resolveTopics(adminHTTPURL, options.Topic, options.Topics, options.TopicsPattern)
+	if err != nil {
+		return nil, newError(InvalidConfiguration, err.Error())
+	}
+
+	queueSize := options.ReceiverQueueSize
+	if queueSize <= 0 {
+		queueSize = defaultReceiverQueueSize
+	}
+
+	r := &reader{
+		client:         client,
+		readCompacted:  options.ReadCompacted,
+		startMessageID: options.StartMessageID,
+		schema:         options.Schema,
+		queueSize:      queueSize,
+		adminHTTPURL:   adminHTTPURL,
+		topicsPattern:  options.TopicsPattern,
+		byTopic:        make(map[string]*readerTopic),
+		messages:       make(chan Message, queueSize*len(topics)),
+	}
+
+	for _, topic := range topics {
+		if err := r.addTopic(topic); err != nil {
+			r.Close()
+			return nil, err
+		}
+	}
+
+	if options.TopicsPattern != "" {
+		period := options.AutoDiscoveryPeriod
+		if period <= 0 {
+			period = defaultAutoDiscoveryPeriod
+		}
+		r.discoveryStop = make(chan struct{})
+		go r.pollDiscovery(period)
+	}
+
+	return r, nil
+}
+
+// startPosition translates startMessageID into the CommandSubscribe.InitialPosition the
+// broker should apply to a brand-new subscription, plus (for a concrete MessageID that
+// isn't one of the Earliest/Latest sentinels) a position to explicitly Seek to afterwards,
+// since InitialPosition itself can only express "earliest" or "latest".
+func startPosition(startMessageID MessageID) (initialPosition pb.InitialPosition, seekTo MessageID) {
+	switch startMessageID {
+	case EarliestMessage:
+		return pb.InitialPosition_Earliest, nil
+	case LatestMessage:
+		return pb.InitialPosition_Latest, nil
+	default:
+		return pb.InitialPosition_Latest, startMessageID
+	}
+}
+
+func (r *reader) addTopic(topic string) error {
+	// Readers get an exclusive, never-durable subscription private to this reader
+	// instance, matching how the C++/Java clients implement Reader on top of Consumer.
+	subscription := fmt.Sprintf("reader-%x", r.client.nextConsumerID())
+
+	initialPosition, seekTo := startPosition(r.startMessageID)
+
+	pc, err := newPartitionConsumer(r.client, topic, subscription, pb.SubType_EXCLUSIVE,
+		r.readCompacted, initialPosition, r.client.nextConsumerID(), r.schema, r.queueSize)
+	if err != nil {
+		return err
+	}
+
+	if seekTo != nil {
+		if err := pc.seek(seekTo); err != nil {
+			_ = pc.close()
+			return err
+		}
+	}
+
+	rt := &readerTopic{pc: pc, stop: make(chan struct{})}
+
+	r.mu.Lock()
+	r.byTopic[topic] = rt
+	r.mu.Unlock()
+
+	go r.pump(rt)
+	return nil
+}
+
+func (r *reader) pump(rt *readerTopic) {
+	for {
+		select {
+		case <-rt.stop:
+			return
+		case msg := <-rt.pc.messages:
+			r.messages <- msg
+			// Ack after delivery, not before: a reader's subscription is exclusive
+			// and non-durable, so a failed ack here just risks the broker
+			// redelivering msg later -- it must never cost the caller the message
+			// itself.
+			_ = rt.pc.ack(msg.ID(), pb.AckType_INDIVIDUAL)
+		}
+	}
+}
+
+// pollDiscovery re-resolves r.topicsPattern every period, adding partitionConsumers for
+// newly matching topics and tearing down ones for topics that no longer match.
+func (r *reader) pollDiscovery(period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	namespace, re, err := parseTopicsPattern(r.topicsPattern)
+	if err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-r.discoveryStop:
+			return
+		case <-ticker.C:
+			topics, err := discoverTopics(r.adminHTTPURL, namespace, re)
+			if err != nil {
+				continue
+			}
+
+			current := make(map[string]bool, len(topics))
+			for _, t := range topics {
+				current[t] = true
+				r.mu.Lock()
+				_, exists := r.byTopic[t]
+				r.mu.Unlock()
+				if !exists {
+					_ = r.addTopic(t)
+				}
+			}
+
+			r.mu.Lock()
+			for t, rt := range r.byTopic {
+				if !current[t] {
+					delete(r.byTopic, t)
+					close(rt.stop)
+					go rt.pc.close()
+				}
+			}
+			r.mu.Unlock()
+		}
+	}
+}
+
+// Topic returns the topic of an arbitrary underlying partition consumer. For a
+// multi-topic reader, use Message.Topic to find the topic a message came from; Topic is
+// mainly useful for the single-topic case.
+func (r *reader) Topic() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for topic := range r.byTopic {
+		return topic
+	}
+	return ""
+}
+
+func (r *reader) HasNext() (bool, error) {
+	return len(r.messages) > 0, nil
+}
+
+func (r *reader) Next(ctx context.Context) (Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case msg := <-r.messages:
+		return msg, nil
+	}
+}
+
+func (r *reader) Close() error {
+	if r.discoveryStop != nil {
+		close(r.discoveryStop)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var firstErr error
+	for _, rt := range r.byTopic {
+		close(rt.stop)
+		if err := rt.pc.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,370 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package connection
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal/pb"
+)
+
+const keepAliveInterval = 30 * time.Second
+
+// MessageHandler is invoked for every CommandMessage frame the connection receives, keyed
+// by the consumer ID the message was delivered to. metadata carries the key, properties
+// and event time that travel alongside cmd in the Pulsar wire protocol's separate
+// MessageMetadata section (see frame.go).
+type MessageHandler func(consumerID uint64, cmd *pb.CommandMessage, metadata *pb.MessageMetadata, payload []byte)
+
+// SendReceiptHandler is invoked for every CommandSendReceipt the connection receives, so
+// the producer that owns the sequence ID can resolve the matching pending send.
+type SendReceiptHandler func(producerID, sequenceID uint64)
+
+// ClosedHandler is invoked once, when the connection is permanently closed, so owners
+// (producers, consumers) can reconnect or fail pending operations.
+type ClosedHandler func(err error)
+
+// RemoteClosedHandler is invoked when the broker asks this connection's producer/consumer
+// to close (CommandCloseProducer/CommandCloseConsumer), e.g. for load-shedding or
+// unloading a bundle. Unlike ClosedHandler, the rest of the connection -- and every other
+// producer/consumer multiplexed onto it -- stays up.
+type RemoteClosedHandler func()
+
+// Connection is a single TCP/TLS connection to a Pulsar broker, speaking the Pulsar
+// binary protocol directly -- no libpulsar/CGo involved.
+type Connection struct {
+	LogicalAddr  string
+	PhysicalAddr string
+
+	conn net.Conn
+
+	writeMu sync.Mutex
+
+	pendingMu sync.Mutex
+	pending   map[uint64]chan *pb.BaseCommand
+	requestID uint64 // atomic
+
+	handlersMu             sync.RWMutex
+	messageHandlers        map[uint64]MessageHandler      // by consumer ID
+	sendReceiptHandlers    map[uint64]SendReceiptHandler  // by producer ID
+	producerClosedHandlers map[uint64]RemoteClosedHandler // by producer ID
+	consumerClosedHandlers map[uint64]RemoteClosedHandler // by consumer ID
+	closedHandlers         []ClosedHandler
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Connect dials a broker and performs the CommandConnect/CommandConnected handshake.
+// auth, if non-nil, is asked for fresh AuthMethodName/AuthData to send on CommandConnect
+// for every call, so providers backed by an expiring credential can refresh it per
+// connection.
+func Connect(logicalAddr, physicalAddr string, tlsConfig *tls.Config, auth Authentication) (*Connection, error) {
+	dialer := net.Dialer{Timeout: 10 * time.Second, KeepAlive: keepAliveInterval}
+
+	var conn net.Conn
+	var err error
+	if tlsConfig != nil {
+		conn, err = tls.DialWithDialer(&dialer, "tcp", physicalAddr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", physicalAddr)
+	}
+	if err != nil {
+		return nil, newError(KindConnect, fmt.Sprintf("connection: dial %s", physicalAddr), err)
+	}
+
+	c := &Connection{
+		LogicalAddr:            logicalAddr,
+		PhysicalAddr:           physicalAddr,
+		conn:                   conn,
+		pending:                make(map[uint64]chan *pb.BaseCommand),
+		messageHandlers:        make(map[uint64]MessageHandler),
+		sendReceiptHandlers:    make(map[uint64]SendReceiptHandler),
+		producerClosedHandlers: make(map[uint64]RemoteClosedHandler),
+		consumerClosedHandlers: make(map[uint64]RemoteClosedHandler),
+		closed:                 make(chan struct{}),
+	}
+
+	go c.readLoop()
+	go c.keepAliveLoop()
+
+	var authMethod string
+	var authData []byte
+	if auth != nil {
+		authMethod = auth.Name()
+		if authData, err = auth.GetData(); err != nil {
+			c.Close(err)
+			return nil, newError(KindConnect, "connection: fetching auth data", err)
+		}
+	}
+
+	connectedCh := make(chan *pb.BaseCommand, 1)
+	reqID := atomic.AddUint64(&c.requestID, 1)
+	c.registerPending(reqID, connectedCh)
+
+	err = c.writeCommand(&pb.BaseCommand{
+		Type: pb.Type_CONNECT,
+		Connect: &pb.CommandConnect{
+			ClientVersion:   "Pulsar Go " + clientVersion,
+			ProtocolVersion: protocolVersion,
+			AuthMethodName:  authMethod,
+			AuthData:        authData,
+		},
+	}, nil, nil)
+	if err != nil {
+		c.Close(err)
+		return nil, err
+	}
+
+	select {
+	case <-connectedCh:
+		return c, nil
+	case <-c.closed:
+		return nil, newError(KindConnect, fmt.Sprintf("connection: closed while connecting to %s", physicalAddr), nil)
+	case <-time.After(10 * time.Second):
+		timeoutErr := newError(KindTimeout, fmt.Sprintf("connection: timed out connecting to %s", physicalAddr), nil)
+		c.Close(timeoutErr)
+		return nil, timeoutErr
+	}
+}
+
+const clientVersion = "0.1.0"
+const protocolVersion = 13
+
+// RegisterProducer wires up the SendReceiptHandler for a single producer sharing this
+// connection, so CommandSendReceipt frames reach the right partitionProducer.
+func (c *Connection) RegisterProducer(producerID uint64, h SendReceiptHandler) {
+	c.handlersMu.Lock()
+	c.sendReceiptHandlers[producerID] = h
+	c.handlersMu.Unlock()
+}
+
+// UnregisterProducer removes a handler installed with RegisterProducer.
+func (c *Connection) UnregisterProducer(producerID uint64) {
+	c.handlersMu.Lock()
+	delete(c.sendReceiptHandlers, producerID)
+	delete(c.producerClosedHandlers, producerID)
+	c.handlersMu.Unlock()
+}
+
+// OnProducerClosed registers h to run if the broker sends CommandCloseProducer for
+// producerID, so that one producer's partitionProducer can react (e.g. reconnect) without
+// tearing down every other producer/consumer sharing this connection.
+func (c *Connection) OnProducerClosed(producerID uint64, h RemoteClosedHandler) {
+	c.handlersMu.Lock()
+	c.producerClosedHandlers[producerID] = h
+	c.handlersMu.Unlock()
+}
+
+// RegisterConsumer wires up the MessageHandler for a single consumer sharing this
+// connection, so CommandMessage frames reach the right partitionConsumer.
+func (c *Connection) RegisterConsumer(consumerID uint64, h MessageHandler) {
+	c.handlersMu.Lock()
+	c.messageHandlers[consumerID] = h
+	c.handlersMu.Unlock()
+}
+
+// UnregisterConsumer removes a handler installed with RegisterConsumer.
+func (c *Connection) UnregisterConsumer(consumerID uint64) {
+	c.handlersMu.Lock()
+	delete(c.messageHandlers, consumerID)
+	delete(c.consumerClosedHandlers, consumerID)
+	c.handlersMu.Unlock()
+}
+
+// OnConsumerClosed registers h to run if the broker sends CommandCloseConsumer for
+// consumerID, so that one consumer's partitionConsumer can react without tearing down
+// every other producer/consumer sharing this connection.
+func (c *Connection) OnConsumerClosed(consumerID uint64, h RemoteClosedHandler) {
+	c.handlersMu.Lock()
+	c.consumerClosedHandlers[consumerID] = h
+	c.handlersMu.Unlock()
+}
+
+// OnClosed registers a callback invoked once the connection has closed, so owners
+// (producers, consumers) can reconnect or fail pending operations.
+func (c *Connection) OnClosed(h ClosedHandler) {
+	c.handlersMu.Lock()
+	c.closedHandlers = append(c.closedHandlers, h)
+	c.handlersMu.Unlock()
+}
+
+// SendRequest sends the command returned by build (called with the next request ID) and
+// blocks until the matching response command arrives or the connection closes.
+func (c *Connection) SendRequest(build func(requestID uint64) *pb.BaseCommand, metadata *pb.MessageMetadata, payload []byte) (*pb.BaseCommand, error) {
+	reqID := atomic.AddUint64(&c.requestID, 1)
+	respCh := make(chan *pb.BaseCommand, 1)
+	c.registerPending(reqID, respCh)
+	defer c.unregisterPending(reqID)
+
+	if err := c.writeCommand(build(reqID), metadata, payload); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-respCh:
+		if resp.Error != nil {
+			return nil, newError(KindUnknown, fmt.Sprintf("connection: %s", resp.Error.Error), fmt.Errorf("%s", resp.Error.Message))
+		}
+		return resp, nil
+	case <-c.closed:
+		return nil, newError(KindConnect, "connection: closed while waiting for response", nil)
+	}
+}
+
+// SendCommand sends a command with no expected response (e.g. CommandSend, CommandFlow).
+// metadata is non-nil only for frames that carry a MessageMetadata section (CommandSend).
+func (c *Connection) SendCommand(cmd *pb.BaseCommand, metadata *pb.MessageMetadata, payload []byte) error {
+	return c.writeCommand(cmd, metadata, payload)
+}
+
+func (c *Connection) writeCommand(cmd *pb.BaseCommand, metadata *pb.MessageMetadata, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return writeFrame(c.conn, cmd, metadata, payload)
+}
+
+func (c *Connection) registerPending(requestID uint64, ch chan *pb.BaseCommand) {
+	c.pendingMu.Lock()
+	c.pending[requestID] = ch
+	c.pendingMu.Unlock()
+}
+
+func (c *Connection) unregisterPending(requestID uint64) {
+	c.pendingMu.Lock()
+	delete(c.pending, requestID)
+	c.pendingMu.Unlock()
+}
+
+func (c *Connection) readLoop() {
+	for {
+		frame, err := readFrame(c.conn)
+		if err != nil {
+			c.Close(err)
+			return
+		}
+
+		cmd := frame.BaseCommand
+		switch cmd.Type {
+		case pb.Type_CONNECTED, pb.Type_PRODUCER_SUCCESS, pb.Type_LOOKUP_RESPONSE, pb.Type_ERROR, pb.Type_SUCCESS:
+			c.dispatchResponse(requestIDOf(cmd), cmd)
+		case pb.Type_SEND_RECEIPT:
+			c.handlersMu.RLock()
+			h := c.sendReceiptHandlers[cmd.SendReceipt.ProducerId]
+			c.handlersMu.RUnlock()
+			if h != nil {
+				h(cmd.SendReceipt.ProducerId, cmd.SendReceipt.SequenceId)
+			}
+		case pb.Type_MESSAGE:
+			c.handlersMu.RLock()
+			h := c.messageHandlers[cmd.Message.ConsumerId]
+			c.handlersMu.RUnlock()
+			if h != nil {
+				h(cmd.Message.ConsumerId, cmd.Message, frame.Metadata, frame.Payload)
+			}
+		case pb.Type_PING:
+			_ = c.writeCommand(&pb.BaseCommand{Type: pb.Type_PONG, Pong: &pb.CommandPong{}}, nil, nil)
+		case pb.Type_CLOSE_PRODUCER:
+			// The broker is asking this one producer to reconnect elsewhere (e.g. for
+			// load-shedding); every other producer/consumer multiplexed onto this
+			// connection is unaffected.
+			c.handlersMu.RLock()
+			h := c.producerClosedHandlers[cmd.CloseProducer.ProducerId]
+			c.handlersMu.RUnlock()
+			if h != nil {
+				h()
+			}
+		case pb.Type_CLOSE_CONSUMER:
+			c.handlersMu.RLock()
+			h := c.consumerClosedHandlers[cmd.CloseConsumer.ConsumerId]
+			c.handlersMu.RUnlock()
+			if h != nil {
+				h()
+			}
+		}
+	}
+}
+
+// requestIDOf extracts the correlating request ID from whichever typed field is set.
+func requestIDOf(cmd *pb.BaseCommand) uint64 {
+	switch {
+	case cmd.Connected != nil:
+		// CommandConnected has no request ID of its own: connect is always the first
+		// pending request on a fresh connection.
+		return 1
+	case cmd.ProducerSuccess != nil:
+		return cmd.ProducerSuccess.RequestId
+	case cmd.LookupTopicResponse != nil:
+		return cmd.LookupTopicResponse.RequestId
+	case cmd.Error != nil:
+		return cmd.Error.RequestId
+	case cmd.Success != nil:
+		return cmd.Success.RequestId
+	default:
+		return 0
+	}
+}
+
+func (c *Connection) dispatchResponse(requestID uint64, cmd *pb.BaseCommand) {
+	c.pendingMu.Lock()
+	ch, ok := c.pending[requestID]
+	c.pendingMu.Unlock()
+	if ok {
+		ch <- cmd
+	}
+}
+
+func (c *Connection) keepAliveLoop() {
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.writeCommand(&pb.BaseCommand{Type: pb.Type_PING, Ping: &pb.CommandPing{}}, nil, nil); err != nil {
+				return
+			}
+		case <-c.closed:
+			return
+		}
+	}
+}
+
+// Close tears down the connection and notifies every ClosedHandler registered with
+// OnClosed, exactly once.
+func (c *Connection) Close(err error) {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		_ = c.conn.Close()
+
+		c.handlersMu.RLock()
+		handlers := append([]ClosedHandler(nil), c.closedHandlers...)
+		c.handlersMu.RUnlock()
+
+		for _, h := range handlers {
+			h(err)
+		}
+	})
+}
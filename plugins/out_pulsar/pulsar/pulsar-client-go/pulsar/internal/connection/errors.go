@@ -0,0 +1,56 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package connection
+
+import "fmt"
+
+// Kind categorizes a connection-level failure so the pulsar package can translate it into
+// the right pulsar.Result code without this package importing pulsar (which would be an
+// import cycle, since pulsar already imports connection).
+type Kind int
+
+const (
+	KindUnknown Kind = iota
+	KindConnect
+	KindLookup
+	KindTimeout
+)
+
+// Error is returned for every failure this package produces that originates from dialing,
+// looking up, or talking to a broker, so callers can recover Kind with errors.As instead of
+// pattern-matching an error string.
+type Error struct {
+	Kind Kind
+	msg  string
+	err  error
+}
+
+func newError(kind Kind, msg string, cause error) *Error {
+	return &Error{Kind: kind, msg: msg, err: cause}
+}
+
+func (e *Error) Error() string {
+	if e.err != nil {
+		return fmt.Sprintf("%s: %s", e.msg, e.err)
+	}
+	return e.msg
+}
+
+func (e *Error) Unwrap() error { return e.err }
@@ -0,0 +1,99 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package connection
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/apache/pulsar-client-go/pulsar/internal/pb"
+)
+
+// LookupService resolves a topic name to the broker currently owning it, following
+// redirects until the broker returns an authoritative answer.
+type LookupService struct {
+	pool       *Pool
+	serviceURL string
+}
+
+// NewLookupService creates a LookupService that dials through pool, using serviceURL (the
+// client's initial pulsar:// or pulsar+ssl:// URL) to reach the first broker.
+func NewLookupService(pool *Pool, serviceURL string) *LookupService {
+	return &LookupService{pool: pool, serviceURL: serviceURL}
+}
+
+// Lookup resolves topic to the logical/physical address of the broker that currently owns
+// it, sending at most maxLookupRedirects lookup requests (so following up to
+// maxLookupRedirects-1 redirects).
+func (l *LookupService) Lookup(topic string) (logicalAddr, physicalAddr string, err error) {
+	u, err := url.Parse(l.serviceURL)
+	if err != nil {
+		return "", "", newError(KindLookup, fmt.Sprintf("lookup: invalid service URL %q", l.serviceURL), err)
+	}
+
+	addr := u.Host
+	const maxLookupRedirects = 20
+
+	for i := 0; i < maxLookupRedirects; i++ {
+		conn, err := l.pool.GetConnection(addr, addr)
+		if err != nil {
+			return "", "", err
+		}
+
+		resp, err := conn.SendRequest(func(requestID uint64) *pb.BaseCommand {
+			return &pb.BaseCommand{
+				Type: pb.Type_LOOKUP,
+				LookupTopic: &pb.CommandLookupTopic{
+					Topic:     topic,
+					RequestId: requestID,
+				},
+			}
+		}, nil, nil)
+		if err != nil {
+			return "", "", err
+		}
+
+		lr := resp.LookupTopicResponse
+		if lr == nil {
+			return "", "", newError(KindLookup, "lookup: unexpected response to CommandLookupTopic", nil)
+		}
+
+		serviceURL := lr.BrokerServiceUrl
+		if l.pool.UsesTLS() {
+			if lr.BrokerServiceUrlTls == "" {
+				return "", "", newError(KindLookup, fmt.Sprintf("lookup: broker returned no TLS brokerServiceUrl for %s", topic), nil)
+			}
+			serviceURL = lr.BrokerServiceUrlTls
+		}
+
+		brokerURL, err := url.Parse(serviceURL)
+		if err != nil {
+			return "", "", newError(KindLookup, fmt.Sprintf("lookup: invalid brokerServiceUrl %q", serviceURL), err)
+		}
+
+		if !lr.Redirect {
+			return brokerURL.Host, brokerURL.Host, nil
+		}
+
+		addr = brokerURL.Host
+	}
+
+	return "", "", newError(KindLookup, fmt.Sprintf("lookup: too many redirects resolving %s", topic), nil)
+}
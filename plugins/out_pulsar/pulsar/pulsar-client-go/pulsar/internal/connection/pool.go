@@ -0,0 +1,102 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package connection
+
+import (
+	"crypto/tls"
+	"sync"
+)
+
+// Authentication supplies the credentials a Pool presents on every connection it dials.
+// pkg/auth.Provider satisfies this interface; it's redeclared here so this package
+// doesn't depend on pkg/auth's TLS-specific extensions.
+type Authentication interface {
+	// Name identifies the authentication method to the broker, e.g. "tls" or "token".
+	Name() string
+
+	// GetData returns the auth_data bytes to send on CommandConnect.
+	GetData() ([]byte, error)
+}
+
+// Pool caches one Connection per logical broker address, so multiple producers/consumers
+// talking to the same broker share a single TCP connection.
+type Pool struct {
+	tlsConfig *tls.Config
+	auth      Authentication
+
+	mu    sync.Mutex
+	conns map[string]*Connection
+}
+
+// NewPool creates a connection Pool that authenticates new connections with auth (may be
+// nil) and, when tlsConfig is non-nil, dials over TLS.
+func NewPool(tlsConfig *tls.Config, auth Authentication) *Pool {
+	return &Pool{
+		tlsConfig: tlsConfig,
+		auth:      auth,
+		conns:     make(map[string]*Connection),
+	}
+}
+
+// GetConnection returns the cached connection for logicalAddr, dialing physicalAddr if
+// there isn't one yet (or the cached one has since closed).
+func (p *Pool) GetConnection(logicalAddr, physicalAddr string) (*Connection, error) {
+	p.mu.Lock()
+	if c, ok := p.conns[logicalAddr]; ok {
+		p.mu.Unlock()
+		select {
+		case <-c.closed:
+			// fall through and redial
+		default:
+			return c, nil
+		}
+		p.mu.Lock()
+	}
+	p.mu.Unlock()
+
+	c, err := Connect(logicalAddr, physicalAddr, p.tlsConfig, p.auth)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.conns[logicalAddr] = c
+	p.mu.Unlock()
+
+	return c, nil
+}
+
+// UsesTLS reports whether this Pool dials its connections over TLS, so callers that see
+// more than one broker URL in a response (e.g. LookupService, picking between a plaintext
+// and a TLS brokerServiceUrl) know which one this Pool can actually connect with.
+func (p *Pool) UsesTLS() bool {
+	return p.tlsConfig != nil
+}
+
+// Close closes every pooled connection.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for addr, c := range p.conns {
+		c.Close(nil)
+		delete(p.conns, addr)
+	}
+}
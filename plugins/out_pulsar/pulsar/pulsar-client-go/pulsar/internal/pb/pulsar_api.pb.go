@@ -0,0 +1,1270 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+// Package pb holds the Go types for the Pulsar wire protocol, normally generated from
+// pulsar_api.proto by protoc-gen-gogo. Until the generator is wired into this build, the
+// subset of commands the pure-Go client needs is hand-maintained here: the same field
+// names, field numbers and protobuf wire-format (un)marshaling the generated code would
+// produce, written by hand against wire.go's helpers instead of protoc-gen-gogo's
+// reflection-based runtime.
+package pb
+
+// BaseCommand is the envelope for every command exchanged on a Pulsar connection. Exactly
+// one of the typed fields below is set, selected by Type.
+type BaseCommand struct {
+	Type Type
+
+	Connect                 *CommandConnect
+	Connected               *CommandConnected
+	Producer                *CommandProducer
+	ProducerSuccess         *CommandProducerSuccess
+	Send                    *CommandSend
+	SendReceipt             *CommandSendReceipt
+	Message                 *CommandMessage
+	Subscribe               *CommandSubscribe
+	Flow                    *CommandFlow
+	Ack                     *CommandAck
+	LookupTopic             *CommandLookupTopic
+	LookupTopicResponse     *CommandLookupTopicResponse
+	CloseProducer           *CommandCloseProducer
+	CloseConsumer           *CommandCloseConsumer
+	Ping                    *CommandPing
+	Pong                    *CommandPong
+	Error                   *CommandError
+	RedeliverUnacknowledged *CommandRedeliverUnacknowledgedMessages
+	Seek                    *CommandSeek
+	Success                 *CommandSuccess
+}
+
+// Type selects which field of BaseCommand is populated.
+type Type int32
+
+const (
+	Type_CONNECT Type = iota
+	Type_CONNECTED
+	Type_PRODUCER
+	Type_PRODUCER_SUCCESS
+	Type_SEND
+	Type_SEND_RECEIPT
+	Type_MESSAGE
+	Type_SUBSCRIBE
+	Type_FLOW
+	Type_ACK
+	Type_LOOKUP
+	Type_LOOKUP_RESPONSE
+	Type_CLOSE_PRODUCER
+	Type_CLOSE_CONSUMER
+	Type_PING
+	Type_PONG
+	Type_ERROR
+	Type_REDELIVER_UNACKNOWLEDGED_MESSAGES
+	Type_SEEK
+	Type_SUCCESS
+)
+
+// BaseCommand field numbers match pulsar_api.proto's BaseCommand message.
+const (
+	fieldCommandType                    = 1
+	fieldCommandConnect                 = 2
+	fieldCommandConnected               = 3
+	fieldCommandSubscribe               = 4
+	fieldCommandProducer                = 5
+	fieldCommandSend                    = 6
+	fieldCommandSendReceipt             = 7
+	fieldCommandMessage                 = 9
+	fieldCommandAck                     = 10
+	fieldCommandFlow                    = 11
+	fieldCommandSuccess                 = 13
+	fieldCommandError                   = 14
+	fieldCommandCloseProducer           = 15
+	fieldCommandCloseConsumer           = 16
+	fieldCommandProducerSuccess         = 17
+	fieldCommandPing                    = 18
+	fieldCommandPong                    = 19
+	fieldCommandRedeliverUnacknowledged = 20
+	fieldCommandLookupTopic             = 23
+	fieldCommandLookupTopicResponse     = 24
+	fieldCommandSeek                    = 28
+)
+
+// Marshal serializes the command using the protobuf wire format.
+func (c *BaseCommand) Marshal() ([]byte, error) {
+	var buf []byte
+	buf = appendVarintField(buf, fieldCommandType, uint64(c.Type))
+	buf = appendMessageField(buf, fieldCommandConnect, marshalPtr(c.Connect))
+	buf = appendMessageField(buf, fieldCommandConnected, marshalPtr(c.Connected))
+	buf = appendMessageField(buf, fieldCommandSubscribe, marshalPtr(c.Subscribe))
+	buf = appendMessageField(buf, fieldCommandProducer, marshalPtr(c.Producer))
+	buf = appendMessageField(buf, fieldCommandSend, marshalPtr(c.Send))
+	buf = appendMessageField(buf, fieldCommandSendReceipt, marshalPtr(c.SendReceipt))
+	buf = appendMessageField(buf, fieldCommandMessage, marshalPtr(c.Message))
+	buf = appendMessageField(buf, fieldCommandAck, marshalPtr(c.Ack))
+	buf = appendMessageField(buf, fieldCommandFlow, marshalPtr(c.Flow))
+	buf = appendMessageField(buf, fieldCommandSuccess, marshalPtr(c.Success))
+	buf = appendMessageField(buf, fieldCommandError, marshalPtr(c.Error))
+	buf = appendMessageField(buf, fieldCommandCloseProducer, marshalPtr(c.CloseProducer))
+	buf = appendMessageField(buf, fieldCommandCloseConsumer, marshalPtr(c.CloseConsumer))
+	buf = appendMessageField(buf, fieldCommandProducerSuccess, marshalPtr(c.ProducerSuccess))
+	buf = appendMessageField(buf, fieldCommandPing, marshalPtr(c.Ping))
+	buf = appendMessageField(buf, fieldCommandPong, marshalPtr(c.Pong))
+	buf = appendMessageField(buf, fieldCommandRedeliverUnacknowledged, marshalPtr(c.RedeliverUnacknowledged))
+	buf = appendMessageField(buf, fieldCommandLookupTopic, marshalPtr(c.LookupTopic))
+	buf = appendMessageField(buf, fieldCommandLookupTopicResponse, marshalPtr(c.LookupTopicResponse))
+	buf = appendMessageField(buf, fieldCommandSeek, marshalPtr(c.Seek))
+	return buf, nil
+}
+
+// Unmarshal parses a command previously produced by Marshal.
+func (c *BaseCommand) Unmarshal(data []byte) error {
+	*c = BaseCommand{}
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case fieldCommandType:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.Type = Type(v)
+		case fieldCommandConnect:
+			c.Connect = &CommandConnect{}
+			return unmarshalSubmessage(data, pos, c.Connect.Unmarshal)
+		case fieldCommandConnected:
+			c.Connected = &CommandConnected{}
+			return unmarshalSubmessage(data, pos, c.Connected.Unmarshal)
+		case fieldCommandSubscribe:
+			c.Subscribe = &CommandSubscribe{}
+			return unmarshalSubmessage(data, pos, c.Subscribe.Unmarshal)
+		case fieldCommandProducer:
+			c.Producer = &CommandProducer{}
+			return unmarshalSubmessage(data, pos, c.Producer.Unmarshal)
+		case fieldCommandSend:
+			c.Send = &CommandSend{}
+			return unmarshalSubmessage(data, pos, c.Send.Unmarshal)
+		case fieldCommandSendReceipt:
+			c.SendReceipt = &CommandSendReceipt{}
+			return unmarshalSubmessage(data, pos, c.SendReceipt.Unmarshal)
+		case fieldCommandMessage:
+			c.Message = &CommandMessage{}
+			return unmarshalSubmessage(data, pos, c.Message.Unmarshal)
+		case fieldCommandAck:
+			c.Ack = &CommandAck{}
+			return unmarshalSubmessage(data, pos, c.Ack.Unmarshal)
+		case fieldCommandFlow:
+			c.Flow = &CommandFlow{}
+			return unmarshalSubmessage(data, pos, c.Flow.Unmarshal)
+		case fieldCommandSuccess:
+			c.Success = &CommandSuccess{}
+			return unmarshalSubmessage(data, pos, c.Success.Unmarshal)
+		case fieldCommandError:
+			c.Error = &CommandError{}
+			return unmarshalSubmessage(data, pos, c.Error.Unmarshal)
+		case fieldCommandCloseProducer:
+			c.CloseProducer = &CommandCloseProducer{}
+			return unmarshalSubmessage(data, pos, c.CloseProducer.Unmarshal)
+		case fieldCommandCloseConsumer:
+			c.CloseConsumer = &CommandCloseConsumer{}
+			return unmarshalSubmessage(data, pos, c.CloseConsumer.Unmarshal)
+		case fieldCommandProducerSuccess:
+			c.ProducerSuccess = &CommandProducerSuccess{}
+			return unmarshalSubmessage(data, pos, c.ProducerSuccess.Unmarshal)
+		case fieldCommandPing:
+			c.Ping = &CommandPing{}
+			return unmarshalSubmessage(data, pos, c.Ping.Unmarshal)
+		case fieldCommandPong:
+			c.Pong = &CommandPong{}
+			return unmarshalSubmessage(data, pos, c.Pong.Unmarshal)
+		case fieldCommandRedeliverUnacknowledged:
+			c.RedeliverUnacknowledged = &CommandRedeliverUnacknowledgedMessages{}
+			return unmarshalSubmessage(data, pos, c.RedeliverUnacknowledged.Unmarshal)
+		case fieldCommandLookupTopic:
+			c.LookupTopic = &CommandLookupTopic{}
+			return unmarshalSubmessage(data, pos, c.LookupTopic.Unmarshal)
+		case fieldCommandLookupTopicResponse:
+			c.LookupTopicResponse = &CommandLookupTopicResponse{}
+			return unmarshalSubmessage(data, pos, c.LookupTopicResponse.Unmarshal)
+		case fieldCommandSeek:
+			c.Seek = &CommandSeek{}
+			return unmarshalSubmessage(data, pos, c.Seek.Unmarshal)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+// marshalPtr marshals m if it's non-nil, returning nil (omitting the field entirely) when
+// it's nil -- every message in this file has a Marshal method with this signature, so
+// generic helpers can use it without a type switch.
+func marshalPtr(m interface{ Marshal() []byte }) []byte {
+	if m == nil {
+		return nil
+	}
+	return m.Marshal()
+}
+
+// unmarshalSubmessage reads the length-delimited bytes for the submessage at *pos and
+// unmarshals them with fn, advancing *pos past the submessage.
+func unmarshalSubmessage(data []byte, pos *int, fn func([]byte) error) error {
+	b, err := readBytes(data, pos)
+	if err != nil {
+		return err
+	}
+	return fn(b)
+}
+
+type CommandConnect struct {
+	ClientVersion   string
+	ProtocolVersion int32
+	AuthMethodName  string
+	AuthData        []byte
+}
+
+func (c *CommandConnect) Marshal() []byte {
+	var buf []byte
+	buf = appendStringField(buf, 1, c.ClientVersion)
+	buf = appendStringField(buf, 3, c.AuthMethodName)
+	buf = appendBytesField(buf, 4, c.AuthData)
+	buf = appendVarintField(buf, 5, uint64(c.ProtocolVersion))
+	return buf
+}
+
+func (c *CommandConnect) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ClientVersion = string(b)
+		case 3:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			c.AuthMethodName = string(b)
+		case 4:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			c.AuthData = append([]byte(nil), b...)
+		case 5:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ProtocolVersion = int32(v)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+type CommandConnected struct {
+	ServerVersion   string
+	ProtocolVersion int32
+	MaxMessageSize  int32
+}
+
+func (c *CommandConnected) Marshal() []byte {
+	var buf []byte
+	buf = appendStringField(buf, 1, c.ServerVersion)
+	buf = appendVarintField(buf, 2, uint64(c.ProtocolVersion))
+	buf = appendVarintField(buf, 3, uint64(c.MaxMessageSize))
+	return buf
+}
+
+func (c *CommandConnected) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ServerVersion = string(b)
+		case 2:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ProtocolVersion = int32(v)
+		case 3:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.MaxMessageSize = int32(v)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+type CommandProducer struct {
+	Topic      string
+	ProducerId uint64
+	RequestId  uint64
+	Properties map[string]string
+	Schema     *Schema
+}
+
+func (c *CommandProducer) Marshal() []byte {
+	var buf []byte
+	buf = appendStringField(buf, 1, c.Topic)
+	buf = appendVarintField(buf, 2, c.ProducerId)
+	buf = appendVarintField(buf, 3, c.RequestId)
+	buf = appendPropertiesField(buf, 6, c.Properties)
+	buf = appendMessageField(buf, 7, marshalPtr(c.Schema))
+	return buf
+}
+
+func (c *CommandProducer) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			c.Topic = string(b)
+		case 2:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ProducerId = v
+		case 3:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.RequestId = v
+		case 6:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			kv, err := unmarshalKeyValue(b)
+			if err != nil {
+				return err
+			}
+			if c.Properties == nil {
+				c.Properties = make(map[string]string)
+			}
+			c.Properties[kv.Key] = kv.Value
+		case 7:
+			c.Schema = &Schema{}
+			return unmarshalSubmessage(data, pos, c.Schema.Unmarshal)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+type CommandProducerSuccess struct {
+	RequestId      uint64
+	ProducerName   string
+	LastSequenceId int64
+}
+
+func (c *CommandProducerSuccess) Marshal() []byte {
+	var buf []byte
+	buf = appendVarintField(buf, 1, c.RequestId)
+	buf = appendStringField(buf, 2, c.ProducerName)
+	buf = appendVarintField(buf, 3, uint64(c.LastSequenceId))
+	return buf
+}
+
+func (c *CommandProducerSuccess) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.RequestId = v
+		case 2:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ProducerName = string(b)
+		case 3:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.LastSequenceId = int64(v)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+// CommandSend is the envelope for a single publish; the message's key, properties,
+// event time and payload travel in the MessageMetadata frame that follows it (see
+// connection/frame.go), not inline here, matching the real Pulsar protocol.
+type CommandSend struct {
+	ProducerId  uint64
+	SequenceId  uint64
+	NumMessages int32
+}
+
+func (c *CommandSend) Marshal() []byte {
+	var buf []byte
+	buf = appendVarintField(buf, 1, c.ProducerId)
+	buf = appendVarintField(buf, 2, c.SequenceId)
+	if c.NumMessages != 0 {
+		buf = appendVarintField(buf, 3, uint64(c.NumMessages))
+	}
+	return buf
+}
+
+func (c *CommandSend) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ProducerId = v
+		case 2:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.SequenceId = v
+		case 3:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.NumMessages = int32(v)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+type CommandSendReceipt struct {
+	ProducerId uint64
+	SequenceId uint64
+	MessageId  *MessageIdData
+}
+
+func (c *CommandSendReceipt) Marshal() []byte {
+	var buf []byte
+	buf = appendVarintField(buf, 1, c.ProducerId)
+	buf = appendVarintField(buf, 2, c.SequenceId)
+	buf = appendMessageField(buf, 3, marshalPtr(c.MessageId))
+	return buf
+}
+
+func (c *CommandSendReceipt) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ProducerId = v
+		case 2:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.SequenceId = v
+		case 3:
+			c.MessageId = &MessageIdData{}
+			return unmarshalSubmessage(data, pos, c.MessageId.Unmarshal)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+// CommandMessage is the envelope for a single delivered message; like CommandSend, the
+// key/properties/event time/redelivery-relevant metadata travels in the MessageMetadata
+// frame that follows it, not inline here.
+type CommandMessage struct {
+	ConsumerId      uint64
+	MessageId       *MessageIdData
+	RedeliveryCount uint32
+}
+
+func (c *CommandMessage) Marshal() []byte {
+	var buf []byte
+	buf = appendVarintField(buf, 1, c.ConsumerId)
+	buf = appendMessageField(buf, 2, marshalPtr(c.MessageId))
+	if c.RedeliveryCount != 0 {
+		buf = appendVarintField(buf, 3, uint64(c.RedeliveryCount))
+	}
+	return buf
+}
+
+func (c *CommandMessage) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ConsumerId = v
+		case 2:
+			c.MessageId = &MessageIdData{}
+			return unmarshalSubmessage(data, pos, c.MessageId.Unmarshal)
+		case 3:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.RedeliveryCount = uint32(v)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+type MessageIdData struct {
+	LedgerId   uint64
+	EntryId    uint64
+	Partition  int32
+	BatchIndex int32
+}
+
+func (m *MessageIdData) Marshal() []byte {
+	var buf []byte
+	buf = appendVarintField(buf, 1, m.LedgerId)
+	buf = appendVarintField(buf, 2, m.EntryId)
+	if m.Partition != 0 {
+		buf = appendVarintField(buf, 3, uint64(uint32(m.Partition)))
+	}
+	if m.BatchIndex != 0 {
+		buf = appendVarintField(buf, 4, uint64(uint32(m.BatchIndex)))
+	}
+	return buf
+}
+
+func (m *MessageIdData) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			m.LedgerId = v
+		case 2:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			m.EntryId = v
+		case 3:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			m.Partition = int32(v)
+		case 4:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			m.BatchIndex = int32(v)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+// MessageMetadata is the per-message frame real Pulsar carries alongside a CommandSend or
+// CommandMessage (see connection/frame.go), separate from BaseCommand.
+type MessageMetadata struct {
+	ProducerName       string
+	SequenceId         uint64
+	PublishTime        uint64
+	Properties         map[string]string
+	PartitionKey       string
+	EventTime          uint64
+	NumMessagesInBatch int32
+}
+
+func (m *MessageMetadata) Marshal() []byte {
+	var buf []byte
+	buf = appendStringField(buf, 1, m.ProducerName)
+	buf = appendVarintField(buf, 2, m.SequenceId)
+	buf = appendVarintField(buf, 3, m.PublishTime)
+	buf = appendPropertiesField(buf, 4, m.Properties)
+	buf = appendStringField(buf, 5, m.PartitionKey)
+	if m.EventTime != 0 {
+		buf = appendVarintField(buf, 16, m.EventTime)
+	}
+	if m.NumMessagesInBatch != 0 {
+		buf = appendVarintField(buf, 17, uint64(m.NumMessagesInBatch))
+	}
+	return buf
+}
+
+func (m *MessageMetadata) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			m.ProducerName = string(b)
+		case 2:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			m.SequenceId = v
+		case 3:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			m.PublishTime = v
+		case 4:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			kv, err := unmarshalKeyValue(b)
+			if err != nil {
+				return err
+			}
+			if m.Properties == nil {
+				m.Properties = make(map[string]string)
+			}
+			m.Properties[kv.Key] = kv.Value
+		case 5:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			m.PartitionKey = string(b)
+		case 16:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			m.EventTime = v
+		case 17:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			m.NumMessagesInBatch = int32(v)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+type CommandSubscribe struct {
+	Topic           string
+	Subscription    string
+	SubType         SubType
+	ConsumerId      uint64
+	RequestId       uint64
+	Schema          *Schema
+	ReadCompacted   bool
+	InitialPosition InitialPosition
+}
+
+// InitialPosition selects where the broker starts a brand-new (never-before-seen)
+// subscription reading from, mirroring pulsar_api.proto's CommandSubscribe.InitialPosition.
+type InitialPosition int32
+
+const (
+	InitialPosition_Latest InitialPosition = iota
+	InitialPosition_Earliest
+)
+
+func (c *CommandSubscribe) Marshal() []byte {
+	var buf []byte
+	buf = appendStringField(buf, 1, c.Topic)
+	buf = appendStringField(buf, 2, c.Subscription)
+	buf = appendVarintField(buf, 3, uint64(c.SubType))
+	buf = appendVarintField(buf, 4, c.ConsumerId)
+	buf = appendVarintField(buf, 5, c.RequestId)
+	buf = appendBoolField(buf, 11, c.ReadCompacted)
+	buf = appendMessageField(buf, 12, marshalPtr(c.Schema))
+	buf = appendVarintField(buf, 13, uint64(c.InitialPosition))
+	return buf
+}
+
+func (c *CommandSubscribe) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			c.Topic = string(b)
+		case 2:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			c.Subscription = string(b)
+		case 3:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.SubType = SubType(v)
+		case 4:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ConsumerId = v
+		case 5:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.RequestId = v
+		case 11:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ReadCompacted = v != 0
+		case 12:
+			c.Schema = &Schema{}
+			return unmarshalSubmessage(data, pos, c.Schema.Unmarshal)
+		case 13:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.InitialPosition = InitialPosition(v)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+type SubType int32
+
+const (
+	SubType_EXCLUSIVE SubType = iota
+	SubType_SHARED
+	SubType_FAILOVER
+	SubType_KEY_SHARED
+)
+
+type CommandFlow struct {
+	ConsumerId     uint64
+	MessagePermits uint32
+}
+
+func (c *CommandFlow) Marshal() []byte {
+	var buf []byte
+	buf = appendVarintField(buf, 1, c.ConsumerId)
+	buf = appendVarintField(buf, 2, uint64(c.MessagePermits))
+	return buf
+}
+
+func (c *CommandFlow) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ConsumerId = v
+		case 2:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.MessagePermits = uint32(v)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+type CommandAck struct {
+	ConsumerId uint64
+	AckType    AckType
+	MessageId  []*MessageIdData
+}
+
+func (c *CommandAck) Marshal() []byte {
+	var buf []byte
+	buf = appendVarintField(buf, 1, c.ConsumerId)
+	buf = appendVarintField(buf, 2, uint64(c.AckType))
+	for _, id := range c.MessageId {
+		buf = appendMessageField(buf, 3, marshalPtr(id))
+	}
+	return buf
+}
+
+func (c *CommandAck) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ConsumerId = v
+		case 2:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.AckType = AckType(v)
+		case 3:
+			id := &MessageIdData{}
+			if err := unmarshalSubmessage(data, pos, id.Unmarshal); err != nil {
+				return err
+			}
+			c.MessageId = append(c.MessageId, id)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+type AckType int32
+
+const (
+	AckType_INDIVIDUAL AckType = iota
+	AckType_CUMULATIVE
+)
+
+type CommandLookupTopic struct {
+	Topic     string
+	RequestId uint64
+}
+
+func (c *CommandLookupTopic) Marshal() []byte {
+	var buf []byte
+	buf = appendStringField(buf, 1, c.Topic)
+	buf = appendVarintField(buf, 2, c.RequestId)
+	return buf
+}
+
+func (c *CommandLookupTopic) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			c.Topic = string(b)
+		case 2:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.RequestId = v
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+type CommandLookupTopicResponse struct {
+	RequestId           uint64
+	BrokerServiceUrl    string
+	BrokerServiceUrlTls string
+	Authoritative       bool
+	Redirect            bool
+}
+
+func (c *CommandLookupTopicResponse) Marshal() []byte {
+	var buf []byte
+	buf = appendStringField(buf, 1, c.BrokerServiceUrl)
+	buf = appendStringField(buf, 2, c.BrokerServiceUrlTls)
+	buf = appendBoolField(buf, 3, c.Redirect)
+	buf = appendBoolField(buf, 4, c.Authoritative)
+	buf = appendVarintField(buf, 5, c.RequestId)
+	return buf
+}
+
+func (c *CommandLookupTopicResponse) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			c.BrokerServiceUrl = string(b)
+		case 2:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			c.BrokerServiceUrlTls = string(b)
+		case 3:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.Redirect = v != 0
+		case 4:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.Authoritative = v != 0
+		case 5:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.RequestId = v
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+type CommandCloseProducer struct {
+	ProducerId uint64
+	RequestId  uint64
+}
+
+func (c *CommandCloseProducer) Marshal() []byte {
+	var buf []byte
+	buf = appendVarintField(buf, 1, c.ProducerId)
+	buf = appendVarintField(buf, 2, c.RequestId)
+	return buf
+}
+
+func (c *CommandCloseProducer) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ProducerId = v
+		case 2:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.RequestId = v
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+type CommandCloseConsumer struct {
+	ConsumerId uint64
+	RequestId  uint64
+}
+
+func (c *CommandCloseConsumer) Marshal() []byte {
+	var buf []byte
+	buf = appendVarintField(buf, 1, c.ConsumerId)
+	buf = appendVarintField(buf, 2, c.RequestId)
+	return buf
+}
+
+func (c *CommandCloseConsumer) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ConsumerId = v
+		case 2:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.RequestId = v
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+type CommandPing struct{}
+
+func (c *CommandPing) Marshal() []byte             { return []byte{} }
+func (c *CommandPing) Unmarshal(data []byte) error { return walkFields(data, skipUnknownField) }
+
+type CommandPong struct{}
+
+func (c *CommandPong) Marshal() []byte             { return []byte{} }
+func (c *CommandPong) Unmarshal(data []byte) error { return walkFields(data, skipUnknownField) }
+
+func skipUnknownField(fieldNum int, wt wireType, data []byte, pos *int) error {
+	return skipField(wt, data, pos)
+}
+
+// CommandRedeliverUnacknowledgedMessages asks the broker to redeliver specific messages
+// (or, if MessageIds is empty, every currently unacknowledged message) on this
+// subscription, used both for explicit Nack and for the broker's own ack-timeout.
+type CommandRedeliverUnacknowledgedMessages struct {
+	ConsumerId uint64
+	MessageIds []*MessageIdData
+}
+
+func (c *CommandRedeliverUnacknowledgedMessages) Marshal() []byte {
+	var buf []byte
+	buf = appendVarintField(buf, 1, c.ConsumerId)
+	for _, id := range c.MessageIds {
+		buf = appendMessageField(buf, 2, marshalPtr(id))
+	}
+	return buf
+}
+
+func (c *CommandRedeliverUnacknowledgedMessages) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ConsumerId = v
+		case 2:
+			id := &MessageIdData{}
+			if err := unmarshalSubmessage(data, pos, id.Unmarshal); err != nil {
+				return err
+			}
+			c.MessageIds = append(c.MessageIds, id)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+// CommandSeek resets a subscription's position to either MessageId or MessagePublishTime;
+// exactly one is set. The broker replies with CommandSuccess.
+type CommandSeek struct {
+	ConsumerId         uint64
+	RequestId          uint64
+	MessageId          *MessageIdData
+	MessagePublishTime int64
+}
+
+func (c *CommandSeek) Marshal() []byte {
+	var buf []byte
+	buf = appendVarintField(buf, 1, c.ConsumerId)
+	buf = appendVarintField(buf, 2, c.RequestId)
+	buf = appendMessageField(buf, 3, marshalPtr(c.MessageId))
+	if c.MessagePublishTime != 0 {
+		buf = appendVarintField(buf, 4, uint64(c.MessagePublishTime))
+	}
+	return buf
+}
+
+func (c *CommandSeek) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.ConsumerId = v
+		case 2:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.RequestId = v
+		case 3:
+			c.MessageId = &MessageIdData{}
+			return unmarshalSubmessage(data, pos, c.MessageId.Unmarshal)
+		case 4:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.MessagePublishTime = int64(v)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+// CommandSuccess is the broker's generic acknowledgement for requests, such as
+// CommandSeek, that don't need a more specific response.
+type CommandSuccess struct {
+	RequestId uint64
+}
+
+func (c *CommandSuccess) Marshal() []byte {
+	var buf []byte
+	buf = appendVarintField(buf, 1, c.RequestId)
+	return buf
+}
+
+func (c *CommandSuccess) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.RequestId = v
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+type CommandError struct {
+	RequestId uint64
+	Error     string
+	Message   string
+}
+
+func (c *CommandError) Marshal() []byte {
+	var buf []byte
+	buf = appendVarintField(buf, 1, c.RequestId)
+	buf = appendStringField(buf, 2, c.Error)
+	buf = appendStringField(buf, 3, c.Message)
+	return buf
+}
+
+func (c *CommandError) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			c.RequestId = v
+		case 2:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			c.Error = string(b)
+		case 3:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			c.Message = string(b)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
+
+// Schema mirrors pulsar.SchemaInfo on the wire.
+type Schema struct {
+	Name       string
+	SchemaData []byte
+	Type       int32
+	Properties map[string]string
+}
+
+func (s *Schema) Marshal() []byte {
+	var buf []byte
+	buf = appendStringField(buf, 1, s.Name)
+	buf = appendBytesField(buf, 3, s.SchemaData)
+	buf = appendVarintField(buf, 4, uint64(s.Type))
+	buf = appendPropertiesField(buf, 5, s.Properties)
+	return buf
+}
+
+func (s *Schema) Unmarshal(data []byte) error {
+	return walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			s.Name = string(b)
+		case 3:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			s.SchemaData = append([]byte(nil), b...)
+		case 4:
+			v, err := readVarint(data, pos)
+			if err != nil {
+				return err
+			}
+			s.Type = int32(v)
+		case 5:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			kv, err := unmarshalKeyValue(b)
+			if err != nil {
+				return err
+			}
+			if s.Properties == nil {
+				s.Properties = make(map[string]string)
+			}
+			s.Properties[kv.Key] = kv.Value
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,214 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// wireType is one of the protobuf wire format's two encodings used by this package
+// (varint and length-delimited; Pulsar's proto never needs 32/64-bit fixed-width fields
+// for the messages this client speaks).
+type wireType uint64
+
+const (
+	wireVarint wireType = 0
+	wireBytes  wireType = 2
+)
+
+func appendVarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	return append(buf, tmp[:n]...)
+}
+
+func appendTag(buf []byte, fieldNum int, wt wireType) []byte {
+	return appendVarint(buf, uint64(fieldNum)<<3|uint64(wt))
+}
+
+// appendVarintField always writes fieldNum, even when v is zero -- required fields (e.g.
+// BaseCommand.Type) must be present on the wire regardless of value.
+func appendVarintField(buf []byte, fieldNum int, v uint64) []byte {
+	buf = appendTag(buf, fieldNum, wireVarint)
+	return appendVarint(buf, v)
+}
+
+func appendBoolField(buf []byte, fieldNum int, v bool) []byte {
+	if !v {
+		return buf
+	}
+	return appendVarintField(buf, fieldNum, 1)
+}
+
+func appendStringField(buf []byte, fieldNum int, v string) []byte {
+	if v == "" {
+		return buf
+	}
+	return appendLenDelimited(buf, fieldNum, []byte(v))
+}
+
+func appendBytesField(buf []byte, fieldNum int, v []byte) []byte {
+	if len(v) == 0 {
+		return buf
+	}
+	return appendLenDelimited(buf, fieldNum, v)
+}
+
+// appendMessageField embeds a submessage that has already been marshaled. A nil payload
+// means the field (and its submessage) is omitted, matching protobuf's "optional message"
+// semantics; a non-nil empty payload is written as a present, empty submessage.
+func appendMessageField(buf []byte, fieldNum int, marshaled []byte) []byte {
+	if marshaled == nil {
+		return buf
+	}
+	return appendLenDelimited(buf, fieldNum, marshaled)
+}
+
+func appendLenDelimited(buf []byte, fieldNum int, v []byte) []byte {
+	buf = appendTag(buf, fieldNum, wireBytes)
+	buf = appendVarint(buf, uint64(len(v)))
+	return append(buf, v...)
+}
+
+// fieldVisitor is called once per (fieldNum, wireType) pair found while walking a
+// marshaled message; it reads the field's value out of data[*pos:] starting there,
+// advances *pos past it, and applies it to the message being unmarshaled.
+type fieldVisitor func(fieldNum int, wt wireType, data []byte, pos *int) error
+
+// walkFields parses data as a sequence of protobuf tag/value pairs, invoking visit for
+// each one. It's the shared decode loop every *.Unmarshal in this package runs.
+func walkFields(data []byte, visit fieldVisitor) error {
+	pos := 0
+	for pos < len(data) {
+		tag, n := binary.Uvarint(data[pos:])
+		if n <= 0 {
+			return fmt.Errorf("pb: invalid field tag at offset %d", pos)
+		}
+		pos += n
+
+		fieldNum := int(tag >> 3)
+		wt := wireType(tag & 0x7)
+
+		if err := visit(fieldNum, wt, data, &pos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readVarint reads a varint-encoded field's value, advancing *pos past it.
+func readVarint(data []byte, pos *int) (uint64, error) {
+	v, n := binary.Uvarint(data[*pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("pb: invalid varint at offset %d", *pos)
+	}
+	*pos += n
+	return v, nil
+}
+
+// readBytes reads a length-delimited field's raw value, advancing *pos past it.
+func readBytes(data []byte, pos *int) ([]byte, error) {
+	length, n := binary.Uvarint(data[*pos:])
+	if n <= 0 {
+		return nil, fmt.Errorf("pb: invalid length at offset %d", *pos)
+	}
+	*pos += n
+
+	end := *pos + int(length)
+	if length > uint64(len(data)) || end > len(data) || end < *pos {
+		return nil, fmt.Errorf("pb: truncated field at offset %d", *pos)
+	}
+	b := data[*pos:end]
+	*pos = end
+	return b, nil
+}
+
+// skipField advances *pos past a field's value without interpreting it, for unknown field
+// numbers -- new proto fields must stay forward-compatible with this hand-maintained
+// client.
+func skipField(wt wireType, data []byte, pos *int) error {
+	switch wt {
+	case wireVarint:
+		_, err := readVarint(data, pos)
+		return err
+	case wireBytes:
+		_, err := readBytes(data, pos)
+		return err
+	default:
+		return fmt.Errorf("pb: unsupported wire type %d", wt)
+	}
+}
+
+// keyValue mirrors Pulsar's KeyValue message, used for every repeated string-map field
+// (CommandProducer.Properties, MessageMetadata.Properties, Schema.Properties, ...).
+type keyValue struct {
+	Key   string
+	Value string
+}
+
+func (kv keyValue) Marshal() []byte {
+	var buf []byte
+	buf = appendStringField(buf, 1, kv.Key)
+	buf = appendStringField(buf, 2, kv.Value)
+	return buf
+}
+
+func unmarshalKeyValue(data []byte) (keyValue, error) {
+	var kv keyValue
+	err := walkFields(data, func(fieldNum int, wt wireType, data []byte, pos *int) error {
+		switch fieldNum {
+		case 1:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			kv.Key = string(b)
+		case 2:
+			b, err := readBytes(data, pos)
+			if err != nil {
+				return err
+			}
+			kv.Value = string(b)
+		default:
+			return skipField(wt, data, pos)
+		}
+		return nil
+	})
+	return kv, err
+}
+
+// appendPropertiesField marshals props as repeated KeyValue submessages under fieldNum, in
+// key order so Marshal is deterministic.
+func appendPropertiesField(buf []byte, fieldNum int, props map[string]string) []byte {
+	if len(props) == 0 {
+		return buf
+	}
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		buf = appendMessageField(buf, fieldNum, keyValue{Key: k, Value: props[k]}.Marshal())
+	}
+	return buf
+}
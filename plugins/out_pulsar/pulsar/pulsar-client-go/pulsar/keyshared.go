@@ -0,0 +1,86 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsar
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+// keyShardRing is a small consistent-hash ring over the topics a multi-topic/regex
+// KeyShared consumer currently spans.
+//
+// Scope: this ring does NOT make messages for a given key stick to one live consumer --
+// that part of KeyShared is, and stays, entirely broker-enforced. Each topic's
+// subscription is already told SubType_KEY_SHARED, and the broker alone decides which
+// client gets a message for a given key; this native client only ever runs one
+// partitionConsumer per topic, so there is no second local delivery target for a client-
+// side hash to choose between within a topic. What the ring actually does is pick a
+// single one of this consumer's topicConsumers to own the client-side Nack/DLQ
+// redelivery bookkeeping for a given message key, so that bookkeeping stays on one node
+// (and survives topics being added or removed by pollDiscovery) when a key happens to
+// appear on more than one of a multi-topic/regex consumer's topics. If true client-side
+// message stickiness is ever needed (e.g. for a future in-process multi-consumer shared
+// subscription), it belongs in handleMessage/addTopic instead of here.
+type keyShardRing struct {
+	nodes  []string
+	hashes []uint32
+}
+
+// newKeyShardRing builds a ring over topics. Order doesn't matter: the nodes are sorted
+// by their own hash so the ring is independent of map iteration order.
+func newKeyShardRing(topics []string) *keyShardRing {
+	r := &keyShardRing{
+		nodes:  append([]string(nil), topics...),
+		hashes: make([]uint32, len(topics)),
+	}
+	for i, t := range r.nodes {
+		r.hashes[i] = hashKey(t)
+	}
+	sort.Sort(r)
+	return r
+}
+
+// owner returns the topic that owns key, or "" if the ring has no nodes.
+func (r *keyShardRing) owner(key string) string {
+	if len(r.nodes) == 0 {
+		return ""
+	}
+
+	h := hashKey(key)
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.nodes[i]
+}
+
+func hashKey(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum32()
+}
+
+func (r *keyShardRing) Len() int      { return len(r.nodes) }
+func (r *keyShardRing) Swap(i, j int) {
+	r.nodes[i], r.nodes[j] = r.nodes[j], r.nodes[i]
+	r.hashes[i], r.hashes[j] = r.hashes[j], r.hashes[i]
+}
+func (r *keyShardRing) Less(i, j int) bool { return r.hashes[i] < r.hashes[j] }
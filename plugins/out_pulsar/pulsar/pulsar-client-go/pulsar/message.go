@@ -0,0 +1,90 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsar
+
+import "time"
+
+// MessageID identifies a single message stored on a topic partition.
+type MessageID interface {
+	// Serialize converts the MessageID into an opaque byte array that can be persisted
+	// and later restored with DeserializeMessageID.
+	Serialize() []byte
+}
+
+type namedMessageID string
+
+func (id namedMessageID) Serialize() []byte { return []byte(id) }
+
+// EarliestMessage is a MessageID referring to the first message retained on a topic.
+var EarliestMessage MessageID = namedMessageID("earliest")
+
+// LatestMessage is a MessageID referring to the most recently published message on a topic.
+var LatestMessage MessageID = namedMessageID("latest")
+
+// Message is a read-only view of a message received from a Reader or Consumer.
+type Message interface {
+	// Topic returns the topic this message was received from.
+	Topic() string
+
+	// Properties returns the user-defined properties attached to the message.
+	Properties() map[string]string
+
+	// Payload returns the raw, un-decoded content of the message.
+	Payload() []byte
+
+	// ID returns the unique identifier of this message within its topic.
+	ID() MessageID
+
+	// PublishTime returns the time at which the producer published the message.
+	PublishTime() time.Time
+
+	// Key returns the routing/compaction key associated with the message, if any.
+	Key() string
+
+	// RedeliveryCount returns how many times this message has previously been delivered
+	// and Nacked or left to time out, as tracked by the broker. Consumer.Nack and
+	// Consumer.ReconsumeLater use it to decide when a message has exhausted
+	// ConsumerOptions.DLQ.MaxDeliveries.
+	RedeliveryCount() uint32
+
+	// GetSchemaValue decodes the message payload into v using the Schema that was
+	// configured on the Reader or Consumer that received it, returning an error if no
+	// schema was configured or the payload doesn't match it.
+	GetSchemaValue(v interface{}) error
+}
+
+// ProducerMessage is the message type accepted by Producer.Send and Producer.SendAsync.
+type ProducerMessage struct {
+	// Payload for the message. Mutually exclusive with Value: set one or the other.
+	Payload []byte
+
+	// Value is run through the producer's Schema (ProducerOptions.Schema) to build
+	// Payload. Ignored if Payload is set directly.
+	Value interface{}
+
+	// Key is used for routing and, combined with Payload, for topic compaction.
+	Key string
+
+	// Properties attaches arbitrary user-defined metadata to the message.
+	Properties map[string]string
+
+	// EventTime overrides the timestamp associated with the message.
+	EventTime *time.Time
+}
@@ -0,0 +1,120 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsar
+
+import (
+	"context"
+	"time"
+)
+
+// MessageRoutingMode selects how a Producer assigns messages without a Key to partitions.
+type MessageRoutingMode int
+
+const (
+	RoundRobinDistribution MessageRoutingMode = iota
+	UseSinglePartition
+	CustomPartition
+)
+
+// HashingScheme selects the hash function used to map a message Key to a partition.
+type HashingScheme int
+
+const (
+	JavaStringHash HashingScheme = iota
+	Murmur3_32Hash
+	BoostHash
+)
+
+// CompressionType selects the compression codec applied to a producer's batches.
+type CompressionType int
+
+const (
+	NoCompression CompressionType = iota
+	LZ4
+	ZLib
+	ZSTD
+)
+
+// TopicMetadata describes a topic as seen by a MessageRouter.
+type TopicMetadata interface {
+	// NumPartitions returns the number of partitions for the topic.
+	NumPartitions() int
+}
+
+// MessageRouter is invoked for every message without an explicit partition to decide which
+// partition index it should be routed to.
+type MessageRouter func(msg Message, metadata TopicMetadata) int
+
+// ProducerOptions configures a Producer created via Client.CreateProducer.
+type ProducerOptions struct {
+	// Topic is the topic this producer will publish to. Required.
+	Topic string
+
+	// Name, if set, overrides the producer name generated by the client.
+	Name string
+
+	// Properties attaches arbitrary metadata to the producer, visible to consumers.
+	Properties map[string]string
+
+	// SendTimeout is accepted but not read by this client: Send waits until the broker
+	// acknowledges the message or ctx is done, and SendAsync's callback has no timeout.
+	SendTimeout time.Duration
+
+	MaxPendingMessages                 int
+	MaxPendingMessagesAcrossPartitions int
+	BlockIfQueueFull                   bool
+	MessageRoutingMode                 MessageRoutingMode
+	HashingScheme                      HashingScheme
+	CompressionType                    CompressionType
+	MessageRouter                      MessageRouter
+
+	Batching                bool
+	BatchingMaxPublishDelay time.Duration
+	BatchingMaxMessages     uint
+
+	// Schema, when set, is used to encode ProducerMessage.Value into Payload and is
+	// advertised to the broker/schema-registry when the producer is created.
+	Schema Schema
+}
+
+// Producer publishes messages to a single topic.
+type Producer interface {
+	// Topic returns the topic this producer is publishing to.
+	Topic() string
+
+	// Name returns the name assigned to this producer.
+	Name() string
+
+	// Send publishes a message, blocking until the broker acknowledges it or ctx is done.
+	Send(ctx context.Context, msg ProducerMessage) error
+
+	// SendAsync publishes a message without waiting for the broker's acknowledgement,
+	// invoking callback once the broker acknowledges it (or the send fails). It blocks
+	// only while the producer's send queue is full.
+	SendAsync(ctx context.Context, msg ProducerMessage, callback func(ProducerMessage, error))
+
+	// Close blocks until the producer has released its resources. Sends still awaiting a
+	// broker acknowledgement are not flushed; their callbacks receive an AlreadyClosed
+	// error.
+	Close() error
+
+	// CloseAsync releases the producer's resources, invoking callback once done.
+	CloseAsync(callback func(error))
+}
@@ -0,0 +1,78 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsar
+
+import (
+	"context"
+	"time"
+)
+
+// ReaderOptions configures a Reader created via Client.CreateReader.
+type ReaderOptions struct {
+	// Topic is the topic to read from. Exactly one of Topic, Topics or TopicsPattern
+	// must be set.
+	Topic string
+
+	// Topics reads from a fixed set of topics, interleaving their messages in
+	// arrival order.
+	Topics []string
+
+	// TopicsPattern reads from every topic matching a regex, e.g.
+	// "persistent://tenant/ns/foo-.*", re-resolved every AutoDiscoveryPeriod. See
+	// ConsumerOptions.TopicsPattern for the pattern syntax.
+	TopicsPattern string
+
+	// AutoDiscoveryPeriod controls how often TopicsPattern is re-resolved. Defaults to
+	// 60 seconds.
+	AutoDiscoveryPeriod time.Duration
+
+	// StartMessageID is the position each underlying topic starts at, typically
+	// EarliestMessage or LatestMessage. Required.
+	StartMessageID MessageID
+
+	// ReceiverQueueSize sets how many messages are prefetched from the broker.
+	ReceiverQueueSize int
+
+	// ReadCompacted instructs the broker to serve the compacted view of the topic, where
+	// only the last message for each Key is returned.
+	ReadCompacted bool
+
+	// Schema, when set, is used by Message.GetSchemaValue to decode messages read from
+	// this topic.
+	Schema Schema
+}
+
+// Reader gives sequential, low-level access to the messages of a topic, bypassing the
+// subscription/ack machinery used by Consumer.
+type Reader interface {
+	// Topic returns the topic this reader is attached to.
+	Topic() string
+
+	// HasNext returns whether at least one message is already buffered locally for Next.
+	// It does not query the broker, so it returns false while a published message is
+	// still in flight to this reader.
+	HasNext() (bool, error)
+
+	// Next blocks until the next message is available, ctx is done, or an error occurs.
+	Next(ctx context.Context) (Message, error)
+
+	// Close releases the resources held by the reader.
+	Close() error
+}
@@ -22,9 +22,10 @@ package pulsar
 import (
 	"context"
 	"fmt"
-	"strings"
 	"testing"
 	"time"
+
+	"github.com/apache/pulsar-client-go/pulsaradmin"
 )
 
 func TestReaderConnectError(t *testing.T) {
@@ -132,6 +133,50 @@ func TestReaderWithInvalidConf(t *testing.T) {
 }
 
 
+func TestReaderMultiTopic(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		URL: "pulsar://localhost:6650",
+	})
+
+	assertNil(t, err)
+	defer client.Close()
+
+	topics := []string{"my-reader-multi-topic-1", "my-reader-multi-topic-2"}
+
+	for _, topic := range topics {
+		producer, err := client.CreateProducer(ProducerOptions{
+			Topic: topic,
+		})
+		assertNil(t, err)
+		defer producer.Close()
+
+		assertNil(t, producer.Send(context.Background(), ProducerMessage{
+			Payload: []byte("hello-" + topic),
+		}))
+	}
+
+	reader, err := client.CreateReader(ReaderOptions{
+		Topics:         topics,
+		StartMessageID: EarliestMessage,
+	})
+
+	assertNil(t, err)
+	defer reader.Close()
+
+	ctx := context.Background()
+	seen := make(map[string]bool)
+	for i := 0; i < len(topics); i++ {
+		msg, err := reader.Next(ctx)
+		assertNil(t, err)
+		assertNotNil(t, msg)
+		seen[string(msg.Payload())] = true
+	}
+
+	for _, topic := range topics {
+		assertEqual(t, seen["hello-"+topic], true)
+	}
+}
+
 func TestReaderCompaction(t *testing.T) {
 	client, err := NewClient(ClientOptions{
 		URL: "pulsar://localhost:6650",
@@ -161,19 +206,22 @@ func TestReaderCompaction(t *testing.T) {
 	}
 
 	// Compact topic and wait for operation to complete
-	url := fmt.Sprintf("http://localhost:8080/admin/v2/persistent/public/default/%s/compaction", topic)
-	makeHttpPutCall(t, url)
+	admin, err := pulsaradmin.NewClient(pulsaradmin.Config{WebServiceURL: "http://localhost:8080"})
+	assertNil(t, err)
+
+	fqTopic := fmt.Sprintf("persistent://public/default/%s", topic)
+	assertNil(t, admin.Topics().Compact(fqTopic))
 	for {
-		res := makeHttpGetCall(t, url)
-		if strings.Contains(res, "RUNNING") {
+		status, err := admin.Topics().CompactionStatus(fqTopic)
+		assertNil(t, err)
+		if status.Status == "RUNNING" {
 			fmt.Println("Compaction still running")
 			time.Sleep(100 * time.Millisecond)
 			continue
-		} else {
-			assertEqual(t, strings.Contains(res, "SUCCESS"), true)
-			fmt.Println("Compaction is done")
-			break
 		}
+		assertEqual(t, status.Status, "SUCCESS")
+		fmt.Println("Compaction is done")
+		break
 	}
 
 	// Restart the consumers
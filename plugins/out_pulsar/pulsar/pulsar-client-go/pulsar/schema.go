@@ -0,0 +1,340 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/linkedin/goavro/v2"
+)
+
+// SchemaType identifies the wire encoding of a Schema, matching the broker's
+// org.apache.pulsar.common.schema.SchemaType enum.
+type SchemaType int
+
+const (
+	NoSchema SchemaType = iota
+	StringSchemaType
+	BytesSchemaType
+	JSONSchemaType
+	ProtobufSchemaType
+	AvroSchemaType
+	KeyValueSchemaType
+)
+
+// SchemaInfo is the metadata sent to the broker/schema-registry when a producer,
+// consumer or reader is created with a Schema.
+type SchemaInfo struct {
+	Name       string
+	Schema     []byte
+	Type       SchemaType
+	Properties map[string]string
+}
+
+// Schema encodes Go values to message payloads and decodes them back, and describes
+// itself to the broker via Schema().
+type Schema interface {
+	// Schema returns the SchemaInfo advertised to the broker for this schema.
+	Schema() *SchemaInfo
+
+	// Encode converts v into a wire payload according to this schema.
+	Encode(v interface{}) ([]byte, error)
+
+	// Decode parses data, as produced by Encode, into v.
+	Decode(data []byte, v interface{}) error
+}
+
+// StringSchema encodes values as their UTF-8 string representation.
+type StringSchema struct {
+	info SchemaInfo
+}
+
+// NewStringSchema creates a Schema that encodes/decodes plain UTF-8 strings.
+func NewStringSchema() *StringSchema {
+	return &StringSchema{info: SchemaInfo{Name: "String", Type: StringSchemaType}}
+}
+
+func (s *StringSchema) Schema() *SchemaInfo { return &s.info }
+
+func (s *StringSchema) Encode(v interface{}) ([]byte, error) {
+	switch val := v.(type) {
+	case string:
+		return []byte(val), nil
+	case []byte:
+		return val, nil
+	default:
+		return nil, fmt.Errorf("pulsar: StringSchema.Encode expects a string, got %T", v)
+	}
+}
+
+func (s *StringSchema) Decode(data []byte, v interface{}) error {
+	ptr, ok := v.(*string)
+	if !ok {
+		return fmt.Errorf("pulsar: StringSchema.Decode expects a *string, got %T", v)
+	}
+	*ptr = string(data)
+	return nil
+}
+
+// BytesSchema passes the payload through unchanged.
+type BytesSchema struct {
+	info SchemaInfo
+}
+
+// NewBytesSchema creates a Schema that passes the raw payload through unchanged.
+func NewBytesSchema() *BytesSchema {
+	return &BytesSchema{info: SchemaInfo{Name: "Bytes", Type: BytesSchemaType}}
+}
+
+func (s *BytesSchema) Schema() *SchemaInfo { return &s.info }
+
+func (s *BytesSchema) Encode(v interface{}) ([]byte, error) {
+	data, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("pulsar: BytesSchema.Encode expects a []byte, got %T", v)
+	}
+	return data, nil
+}
+
+func (s *BytesSchema) Decode(data []byte, v interface{}) error {
+	ptr, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("pulsar: BytesSchema.Decode expects a *[]byte, got %T", v)
+	}
+	*ptr = data
+	return nil
+}
+
+// JSONSchema encodes/decodes values as JSON. Its JSON schema definition is advertised to
+// the broker; values are not validated against it locally.
+type JSONSchema struct {
+	info SchemaInfo
+}
+
+// NewJSONSchema creates a Schema that marshals values as JSON. schemaDef is the JSON
+// schema definition advertised to the broker; it is not used to validate locally.
+func NewJSONSchema(schemaDef string, properties map[string]string) *JSONSchema {
+	return &JSONSchema{info: SchemaInfo{
+		Name:       "JSON",
+		Schema:     []byte(schemaDef),
+		Type:       JSONSchemaType,
+		Properties: properties,
+	}}
+}
+
+func (s *JSONSchema) Schema() *SchemaInfo { return &s.info }
+
+func (s *JSONSchema) Encode(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (s *JSONSchema) Decode(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// ProtoSchema encodes/decodes values implementing proto.Message using the protobuf
+// wire format.
+type ProtoSchema struct {
+	info SchemaInfo
+}
+
+// NewProtoSchema creates a Schema for a protobuf message type. schemaDef is the
+// .proto-derived descriptor advertised to the broker.
+func NewProtoSchema(schemaDef string, properties map[string]string) *ProtoSchema {
+	return &ProtoSchema{info: SchemaInfo{
+		Name:       "Protobuf",
+		Schema:     []byte(schemaDef),
+		Type:       ProtobufSchemaType,
+		Properties: properties,
+	}}
+}
+
+func (s *ProtoSchema) Schema() *SchemaInfo { return &s.info }
+
+func (s *ProtoSchema) Encode(v interface{}) ([]byte, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("pulsar: ProtoSchema.Encode expects a proto.Message, got %T", v)
+	}
+	return proto.Marshal(msg)
+}
+
+func (s *ProtoSchema) Decode(data []byte, v interface{}) error {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("pulsar: ProtoSchema.Decode expects a proto.Message, got %T", v)
+	}
+	return proto.Unmarshal(data, msg)
+}
+
+// AvroSchema encodes/decodes values against an Avro schema definition.
+type AvroSchema struct {
+	info  SchemaInfo
+	codec *goavro.Codec
+}
+
+// NewAvroSchema creates a Schema that encodes/decodes Avro records described by
+// schemaDef, an Avro schema JSON document.
+func NewAvroSchema(schemaDef string, properties map[string]string) (*AvroSchema, error) {
+	codec, err := goavro.NewCodec(schemaDef)
+	if err != nil {
+		return nil, fmt.Errorf("pulsar: invalid Avro schema: %w", err)
+	}
+
+	return &AvroSchema{
+		codec: codec,
+		info: SchemaInfo{
+			Name:       "Avro",
+			Schema:     []byte(schemaDef),
+			Type:       AvroSchemaType,
+			Properties: properties,
+		},
+	}, nil
+}
+
+func (s *AvroSchema) Schema() *SchemaInfo { return &s.info }
+
+func (s *AvroSchema) Encode(v interface{}) ([]byte, error) {
+	native, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("pulsar: AvroSchema.Encode expects a map[string]interface{}, got %T", v)
+	}
+	return s.codec.BinaryFromNative(nil, native)
+}
+
+func (s *AvroSchema) Decode(data []byte, v interface{}) error {
+	ptr, ok := v.(*map[string]interface{})
+	if !ok {
+		return fmt.Errorf("pulsar: AvroSchema.Decode expects a *map[string]interface{}, got %T", v)
+	}
+
+	native, _, err := s.codec.NativeFromBinary(data)
+	if err != nil {
+		return err
+	}
+
+	record, ok := native.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("pulsar: decoded Avro value is not a record: %T", native)
+	}
+
+	*ptr = record
+	return nil
+}
+
+// KeyValue pairs a message Key with a Value to be (de)serialized together by
+// KeyValueSchema.
+type KeyValue struct {
+	Key   interface{}
+	Value interface{}
+}
+
+// KeyValueSchema composes a key Schema and a value Schema, concatenating their
+// length-prefixed encodings into a single payload.
+type KeyValueSchema struct {
+	info        SchemaInfo
+	KeySchema   Schema
+	ValueSchema Schema
+}
+
+// NewKeyValueSchema creates a Schema that (de)serializes a KeyValue using keySchema for
+// the Key and valueSchema for the Value.
+func NewKeyValueSchema(keySchema, valueSchema Schema) *KeyValueSchema {
+	return &KeyValueSchema{
+		info:        SchemaInfo{Name: "KeyValue", Type: KeyValueSchemaType},
+		KeySchema:   keySchema,
+		ValueSchema: valueSchema,
+	}
+}
+
+func (s *KeyValueSchema) Schema() *SchemaInfo { return &s.info }
+
+func (s *KeyValueSchema) Encode(v interface{}) ([]byte, error) {
+	kv, ok := v.(KeyValue)
+	if !ok {
+		return nil, fmt.Errorf("pulsar: KeyValueSchema.Encode expects a KeyValue, got %T", v)
+	}
+
+	keyBytes, err := s.KeySchema.Encode(kv.Key)
+	if err != nil {
+		return nil, fmt.Errorf("pulsar: encoding key: %w", err)
+	}
+
+	valueBytes, err := s.ValueSchema.Encode(kv.Value)
+	if err != nil {
+		return nil, fmt.Errorf("pulsar: encoding value: %w", err)
+	}
+
+	var buf bytes.Buffer
+	writeUint32(&buf, uint32(len(keyBytes)))
+	buf.Write(keyBytes)
+	writeUint32(&buf, uint32(len(valueBytes)))
+	buf.Write(valueBytes)
+	return buf.Bytes(), nil
+}
+
+func (s *KeyValueSchema) Decode(data []byte, v interface{}) error {
+	ptr, ok := v.(*KeyValue)
+	if !ok {
+		return fmt.Errorf("pulsar: KeyValueSchema.Decode expects a *KeyValue, got %T", v)
+	}
+
+	keyLen, rest, err := readUint32(data)
+	if err != nil {
+		return err
+	}
+	if uint32(len(rest)) < keyLen {
+		return fmt.Errorf("pulsar: truncated key-value payload")
+	}
+	keyBytes, rest := rest[:keyLen], rest[keyLen:]
+
+	valueLen, rest, err := readUint32(rest)
+	if err != nil {
+		return err
+	}
+	if uint32(len(rest)) < valueLen {
+		return fmt.Errorf("pulsar: truncated key-value payload")
+	}
+	valueBytes := rest[:valueLen]
+
+	// The key and value are left as their raw, still-encoded bytes: unlike a plain
+	// Schema, KeySchema/ValueSchema don't know what Go type the caller wants to decode
+	// into, so callers decode each half themselves with KeySchema.Decode/ValueSchema.Decode.
+	*ptr = KeyValue{Key: keyBytes, Value: valueBytes}
+	return nil
+}
+
+func writeUint32(buf *bytes.Buffer, n uint32) {
+	buf.WriteByte(byte(n >> 24))
+	buf.WriteByte(byte(n >> 16))
+	buf.WriteByte(byte(n >> 8))
+	buf.WriteByte(byte(n))
+}
+
+func readUint32(data []byte) (uint32, []byte, error) {
+	if len(data) < 4 {
+		return 0, nil, fmt.Errorf("pulsar: truncated key-value payload")
+	}
+	n := uint32(data[0])<<24 | uint32(data[1])<<16 | uint32(data[2])<<8 | uint32(data[3])
+	return n, data[4:], nil
+}
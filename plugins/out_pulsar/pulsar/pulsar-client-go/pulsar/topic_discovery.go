@@ -0,0 +1,140 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsar
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// adminHTTPTimeout bounds each call to the broker's admin HTTP API, so a TopicsPattern
+// consumer/reader's discovery goroutine can't block forever behind an unreachable broker.
+const adminHTTPTimeout = 30 * time.Second
+
+var adminHTTPClient = &http.Client{Timeout: adminHTTPTimeout}
+
+// parseTopicsPattern splits a pattern like "persistent://tenant/ns/foo-.*" into the
+// tenant/namespace it lives under (a literal prefix) and the regexp matched against each
+// topic's local (last path segment) name.
+func parseTopicsPattern(pattern string) (namespace string, localNameRegexp *regexp.Regexp, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(pattern, "persistent://"), "/", 3)
+	if len(parts) != 3 {
+		return "", nil, fmt.Errorf("pulsar: invalid topics pattern %q, expected persistent://tenant/namespace/regexp", pattern)
+	}
+
+	re, err := regexp.Compile(parts[2])
+	if err != nil {
+		return "", nil, fmt.Errorf("pulsar: invalid topics pattern %q: %w", pattern, err)
+	}
+
+	return parts[0] + "/" + parts[1], re, nil
+}
+
+// adminHTTPURLFromServiceURL derives the broker admin HTTP endpoint from a pulsar://
+// service URL, assuming the admin API is served on the conventional port 8080.
+func adminHTTPURLFromServiceURL(serviceURL string) string {
+	u, err := url.Parse(serviceURL)
+	if err != nil {
+		return "http://localhost:8080"
+	}
+	return fmt.Sprintf("http://%s:8080", u.Hostname())
+}
+
+// discoverTopics queries the broker's getListOfTopics admin endpoint for every persistent
+// topic under namespace (tenant/namespace) and returns the ones whose local name matches
+// re, as fully-qualified persistent:// topic names.
+func discoverTopics(adminHTTPURL, namespace string, re *regexp.Regexp) ([]string, error) {
+	reqURL := fmt.Sprintf("%s/admin/v2/persistent/%s", strings.TrimRight(adminHTTPURL, "/"), namespace)
+
+	resp, err := adminHTTPClient.Get(reqURL)
+	if err != nil {
+		return nil, fmt.Errorf("pulsar: listing topics under %s: %w", namespace, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pulsar: listing topics under %s: admin API returned %s", namespace, resp.Status)
+	}
+
+	var topics []string
+	if err := json.NewDecoder(resp.Body).Decode(&topics); err != nil {
+		return nil, fmt.Errorf("pulsar: decoding topic list for %s: %w", namespace, err)
+	}
+
+	var matched []string
+	for _, t := range topics {
+		local := t
+		if idx := strings.LastIndex(t, "/"); idx >= 0 {
+			local = t[idx+1:]
+		}
+		if re.MatchString(local) {
+			matched = append(matched, fmt.Sprintf("persistent://%s/%s", namespace, local))
+		}
+	}
+	return matched, nil
+}
+
+// resolveTopics turns the Topic/Topics/TopicsPattern trio from ConsumerOptions/
+// ReaderOptions into a concrete, non-empty list of fully-qualified topics ("my-topic"
+// becomes "persistent://public/default/my-topic"). Exactly one of the three must be set.
+func resolveTopics(adminHTTPURL, topic string, topics []string, topicsPattern string) ([]string, error) {
+	qualify := func(t string) string {
+		switch {
+		case strings.Contains(t, "://"):
+			return t
+		case strings.Count(t, "/") == 2:
+			return "persistent://" + t
+		default:
+			return "persistent://public/default/" + t
+		}
+	}
+
+	set := 0
+	for _, isSet := range []bool{topic != "", len(topics) > 0, topicsPattern != ""} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("pulsar: exactly one of Topic, Topics or TopicsPattern is required")
+	}
+
+	switch {
+	case topic != "":
+		return []string{qualify(topic)}, nil
+	case len(topics) > 0:
+		qualified := make([]string, len(topics))
+		for i, t := range topics {
+			qualified[i] = qualify(t)
+		}
+		return qualified, nil
+	default:
+		namespace, re, err := parseTopicsPattern(topicsPattern)
+		if err != nil {
+			return nil, err
+		}
+		return discoverTopics(adminHTTPURL, namespace, re)
+	}
+}
@@ -0,0 +1,49 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsaradmin
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// RetentionPolicies configures how long acked messages are kept on a namespace's topics
+// before being dropped.
+type RetentionPolicies struct {
+	RetentionTimeInMinutes int `json:"retentionTimeInMinutes"`
+	RetentionSizeInMB      int `json:"retentionSizeInMB"`
+}
+
+// NamespacesClient drives the broker's per-namespace admin operations.
+type NamespacesClient interface {
+	// SetRetention sets namespace's (tenant/namespace) retention policy.
+	SetRetention(namespace string, policies RetentionPolicies) error
+}
+
+type namespaces struct{ client *client }
+
+func (n *namespaces) SetRetention(namespace string, policies RetentionPolicies) error {
+	tenant, name, err := splitNamespace(namespace)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/admin/v2/namespaces/%s/%s/retention", tenant, name)
+	return n.client.doJSON(http.MethodPost, path, policies, nil)
+}
@@ -0,0 +1,103 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsaradmin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/apache/pulsar-client-go/pkg/auth"
+)
+
+// doJSON issues a method request against path (relative to c.baseURL), marshaling body
+// (if non-nil) as the JSON request payload and decoding the response into out (if
+// non-nil). It's the one place request construction, authentication and error wrapping
+// happen, so every *Client method stays a one-liner.
+func (c *client) doJSON(method, path string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("pulsaradmin: encoding request body for %s: %w", path, err)
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("pulsaradmin: building request for %s: %w", path, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.auth != nil {
+		// A CertificateProvider authenticates via the TLS handshake (see NewClient);
+		// everything else sends its data as a bearer token on each request.
+		if _, ok := c.auth.(auth.CertificateProvider); !ok {
+			if data, err := c.auth.GetData(); err == nil && len(data) > 0 {
+				req.Header.Set("Authorization", "Bearer "+string(data))
+			}
+		}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("pulsaradmin: %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("pulsaradmin: %s %s: admin API returned %s: %s",
+			method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("pulsaradmin: decoding response for %s: %w", path, err)
+	}
+	return nil
+}
+
+// splitTopic breaks a persistent://tenant/namespace/name topic into its three parts.
+func splitTopic(topic string) (tenant, namespace, name string, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(topic, "persistent://"), "/", 3)
+	if len(parts) != 3 {
+		return "", "", "", fmt.Errorf(
+			"pulsaradmin: invalid topic %q, expected persistent://tenant/namespace/name", topic)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// splitNamespace breaks a tenant/namespace namespace name into its two parts.
+func splitNamespace(namespace string) (tenant, name string, err error) {
+	parts := strings.SplitN(namespace, "/", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf(
+			"pulsaradmin: invalid namespace %q, expected tenant/namespace", namespace)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,71 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsaradmin
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// SchemaInfo is the schema-registry's representation of a topic's schema, as returned by
+// SchemasClient.Get and accepted by SchemasClient.Post.
+type SchemaInfo struct {
+	Type       string            `json:"type"`
+	Schema     string            `json:"schema"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// SchemasClient drives the broker's schema-registry admin operations for a topic.
+type SchemasClient interface {
+	// Get returns topic's current schema.
+	Get(topic string) (SchemaInfo, error)
+
+	// Post registers info as topic's schema.
+	Post(topic string, info SchemaInfo) error
+}
+
+type schemas struct{ client *client }
+
+func (s *schemas) Get(topic string) (SchemaInfo, error) {
+	path, err := schemaPath(topic)
+	if err != nil {
+		return SchemaInfo{}, err
+	}
+	var info SchemaInfo
+	err = s.client.doJSON(http.MethodGet, path, nil, &info)
+	return info, err
+}
+
+func (s *schemas) Post(topic string, info SchemaInfo) error {
+	path, err := schemaPath(topic)
+	if err != nil {
+		return err
+	}
+	return s.client.doJSON(http.MethodPost, path, info, nil)
+}
+
+// schemaPath builds the admin/v2 schema-registry path for topic.
+func schemaPath(topic string) (string, error) {
+	tenant, namespace, name, err := splitTopic(topic)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/admin/v2/schemas/%s/%s/%s/schema", tenant, namespace, name), nil
+}
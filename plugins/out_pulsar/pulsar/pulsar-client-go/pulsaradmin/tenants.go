@@ -0,0 +1,40 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsaradmin
+
+import "net/http"
+
+// TenantInfo describes a tenant's allowed clusters and admin roles.
+type TenantInfo struct {
+	AdminRoles      []string `json:"adminRoles,omitempty"`
+	AllowedClusters []string `json:"allowedClusters,omitempty"`
+}
+
+// TenantsClient drives the broker's per-tenant admin operations.
+type TenantsClient interface {
+	// Create creates tenant with the given info.
+	Create(tenant string, info TenantInfo) error
+}
+
+type tenants struct{ client *client }
+
+func (t *tenants) Create(tenant string, info TenantInfo) error {
+	return t.client.doJSON(http.MethodPut, "/admin/v2/tenants/"+tenant, info, nil)
+}
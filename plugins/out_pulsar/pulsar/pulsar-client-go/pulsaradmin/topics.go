@@ -0,0 +1,111 @@
+//
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+//
+
+package pulsaradmin
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// LongRunningStatus reports the progress of a broker operation that runs in the
+// background, such as topic compaction.
+type LongRunningStatus struct {
+	Status    string `json:"status"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+// TopicStats is the broker's point-in-time message/throughput counters for a topic.
+type TopicStats struct {
+	MsgRateIn        float64 `json:"msgRateIn"`
+	MsgRateOut       float64 `json:"msgRateOut"`
+	MsgThroughputIn  float64 `json:"msgThroughputIn"`
+	MsgThroughputOut float64 `json:"msgThroughputOut"`
+	StorageSize      int64   `json:"storageSize"`
+	BacklogSize      int64   `json:"backlogSize"`
+}
+
+// TopicsClient drives the broker's per-topic admin operations.
+type TopicsClient interface {
+	// Compact triggers compaction of topic's backlog. Compaction runs asynchronously;
+	// poll CompactionStatus for progress.
+	Compact(topic string) error
+
+	// CompactionStatus reports the progress of the most recent Compact call.
+	CompactionStatus(topic string) (LongRunningStatus, error)
+
+	// Unload asks the broker to close topic's ownership and let it be reassigned, e.g.
+	// after changing its configuration.
+	Unload(topic string) error
+
+	// Stats returns topic's current message/throughput counters.
+	Stats(topic string) (TopicStats, error)
+}
+
+type topics struct{ client *client }
+
+func (t *topics) Compact(topic string) error {
+	path, err := topicPath(topic, "compaction")
+	if err != nil {
+		return err
+	}
+	return t.client.doJSON(http.MethodPut, path, nil, nil)
+}
+
+func (t *topics) CompactionStatus(topic string) (LongRunningStatus, error) {
+	path, err := topicPath(topic, "compaction")
+	if err != nil {
+		return LongRunningStatus{}, err
+	}
+	var status LongRunningStatus
+	err = t.client.doJSON(http.MethodGet, path, nil, &status)
+	return status, err
+}
+
+func (t *topics) Unload(topic string) error {
+	path, err := topicPath(topic, "unload")
+	if err != nil {
+		return err
+	}
+	return t.client.doJSON(http.MethodPut, path, nil, nil)
+}
+
+func (t *topics) Stats(topic string) (TopicStats, error) {
+	path, err := topicPath(topic, "stats")
+	if err != nil {
+		return TopicStats{}, err
+	}
+	var stats TopicStats
+	err = t.client.doJSON(http.MethodGet, path, nil, &stats)
+	return stats, err
+}
+
+// topicPath builds the admin/v2 path for topic, optionally with a trailing sub-resource
+// such as "compaction" or "stats".
+func topicPath(topic, subResource string) (string, error) {
+	tenant, namespace, name, err := splitTopic(topic)
+	if err != nil {
+		return "", err
+	}
+	path := fmt.Sprintf("/admin/v2/persistent/%s/%s/%s", tenant, namespace, name)
+	if subResource != "" {
+		path += "/" + subResource
+	}
+	return path, nil
+}